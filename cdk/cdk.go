@@ -1,6 +1,7 @@
 package main
 
 import (
+	_ "embed"
 	"os"
 
 	"github.com/aws/aws-cdk-go/awscdk/v2"
@@ -30,6 +31,19 @@ const (
 	googleCalendarID       = "0be6e6b6ae9393690297bb01e75a3efd7a0cd078913ae72522269ad6cbc82ba9@group.calendar.google.com"
 )
 
+// serveCompressedCode picks the compressed copy of a file based on Accept-Encoding
+//
+//go:embed serve-compressed.js
+var serveCompressedCode string
+
+// servePrecompressed is whether CloudFront serves the compressed copies the update function publishes. It is on in
+// cdk.json, but a stack that has never published them has to be deployed with it off and run once first, or requests
+// would be rewritten to copies that don't exist yet.
+func servePrecompressed(stack awscdk.Stack) bool {
+	value := stack.Node().TryGetContext(jsii.String("servePrecompressed"))
+	return value == true || value == "true"
+}
+
 type CdkStackProps struct {
 	awscdk.StackProps
 }
@@ -73,14 +87,35 @@ func NewCdkStack(scope constructs.Construct, id string, props *CdkStackProps) aw
 		Validation: awscertificatemanager.CertificateValidation_FromDns(hostedZone),
 	})
 
+	defaultBehavior := &awscloudfront.BehaviorOptions{
+		Origin:               awscloudfrontorigins.S3BucketOrigin_WithOriginAccessIdentity(bucket, &awscloudfrontorigins.S3BucketOriginWithOAIProps{OriginAccessIdentity: originAccessIdentity}),
+		Compress:             jsii.Bool(true),
+		AllowedMethods:       awscloudfront.AllowedMethods_ALLOW_GET_HEAD(),
+		ViewerProtocolPolicy: awscloudfront.ViewerProtocolPolicy_REDIRECT_TO_HTTPS,
+	}
+
+	if servePrecompressed(stack) {
+		serveCompressed := awscloudfront.NewFunction(stack, jsii.String("ServeCompressedFunction"), &awscloudfront.FunctionProps{
+			Code:    awscloudfront.FunctionCode_FromInline(jsii.String(serveCompressedCode)),
+			Runtime: awscloudfront.FunctionRuntime_JS_2_0(),
+		})
+		defaultBehavior.FunctionAssociations = &[]*awscloudfront.FunctionAssociation{
+			{Function: serveCompressed, EventType: awscloudfront.FunctionEventType_VIEWER_REQUEST},
+		}
+
+		// the path is picked based on Accept-Encoding, so caches between us and the browser need to know that too
+		defaultBehavior.ResponseHeadersPolicy = awscloudfront.NewResponseHeadersPolicy(stack, jsii.String("VaryAcceptEncoding"), &awscloudfront.ResponseHeadersPolicyProps{
+			CustomHeadersBehavior: &awscloudfront.ResponseCustomHeadersBehavior{
+				CustomHeaders: &[]*awscloudfront.ResponseCustomHeader{
+					{Header: jsii.String("Vary"), Value: jsii.String("Accept-Encoding"), Override: jsii.Bool(true)},
+				},
+			},
+		})
+	}
+
 	distribution := awscloudfront.NewDistribution(stack, jsii.String("CloudfrontWebsiteDistribution"), &awscloudfront.DistributionProps{
-		DefaultRootObject: jsii.String("index.html"),
-		DefaultBehavior: &awscloudfront.BehaviorOptions{
-			Origin:               awscloudfrontorigins.S3BucketOrigin_WithOriginAccessIdentity(bucket, &awscloudfrontorigins.S3BucketOriginWithOAIProps{OriginAccessIdentity: originAccessIdentity}),
-			Compress:             jsii.Bool(true),
-			AllowedMethods:       awscloudfront.AllowedMethods_ALLOW_GET_HEAD(),
-			ViewerProtocolPolicy: awscloudfront.ViewerProtocolPolicy_REDIRECT_TO_HTTPS,
-		},
+		DefaultRootObject:      jsii.String("index.html"),
+		DefaultBehavior:        defaultBehavior,
 		DomainNames:            jsii.Strings(domainName),
 		Certificate:            cert,
 		MinimumProtocolVersion: awscloudfront.SecurityPolicyProtocol_TLS_V1_2_2021,
@@ -120,13 +155,17 @@ func NewCdkStack(scope constructs.Construct, id string, props *CdkStackProps) aw
 			"WBNA_API_KEY_SECRET_NAME":         jsii.String(wbnaSecretName),
 			"GOOGLE_CALENDAR_ID":               jsii.String(googleCalendarID),
 			"GOOGLE_CREDENTIALS_SECRET_NAME":   jsii.String(googleAuthSecretName),
+			"PUBLISH_COMPRESSED":               jsii.String("true"),
 		},
 		LoggingFormat:   awslambda.LoggingFormat_JSON,
 		InsightsVersion: awslambda.LambdaInsightsVersion_VERSION_1_0_317_0(),
 		Tracing:         awslambda.Tracing_ACTIVE,
 	})
 
-	bucket.GrantWrite(updateFunction, nil, nil)
+	// read is needed to build on what is already published (the feed, the archive and the sitemap) and to skip uploading
+	// things that haven't changed. It includes listing the bucket, without which S3 reports a key that hasn't been
+	// published yet as AccessDenied instead of NoSuchKey, and we'd have no way to tell the two apart.
+	bucket.GrantReadWrite(updateFunction, nil)
 	distribution.GrantCreateInvalidation(updateFunction)
 	notificationSecret.GrantRead(updateFunction, nil)
 	ticketmasterSecret.GrantRead(updateFunction, nil)
@@ -0,0 +1,28 @@
+// Serves the brotli or gzip copy the update function publishes next to each HTML, JSON and calendar file, when the
+// browser accepts it. Archive days and releases are skipped since days archived before compression was turned on don't
+// have compressed copies, and they're never published again to get them.
+var compressible = /\.(html|json|ics)$/;
+
+function handler(event) {
+    var request = event.request;
+
+    var uri = request.uri;
+    if (uri.endsWith("/")) {
+        uri += "index.html";
+    }
+    request.uri = uri;
+
+    if (!compressible.test(uri) || uri.startsWith("/archive/") || uri.startsWith("/releases/")) {
+        return request;
+    }
+
+    var acceptEncoding = request.headers["accept-encoding"];
+    var accepted = acceptEncoding ? acceptEncoding.value : "";
+    if (/\bbr\b/.test(accepted)) {
+        request.uri = uri + ".br";
+    } else if (/\bgzip\b/.test(accepted)) {
+        request.uri = uri + ".gz";
+    }
+
+    return request;
+}
@@ -0,0 +1,49 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+
+	gcalendar "google.golang.org/api/calendar/v3"
+)
+
+// eventService is the part of the Google Calendar events API we use. It is an interface so tests can fake it.
+type eventService interface {
+	// Get returns the entry with the given ID, or a *googleapi.Error with a 404 code if there isn't one
+	Get(ctx context.Context, calendarID string, eventID string) (*gcalendar.Event, error)
+
+	// FindByProperty returns every entry (including cancelled ones) with the given private extended property
+	FindByProperty(ctx context.Context, calendarID string, key string, value string) ([]*gcalendar.Event, error)
+
+	Insert(ctx context.Context, calendarID string, event *gcalendar.Event) (*gcalendar.Event, error)
+	Update(ctx context.Context, calendarID string, eventID string, event *gcalendar.Event) (*gcalendar.Event, error)
+}
+
+// googleEventService is the real events API
+type googleEventService struct {
+	events *gcalendar.EventsService
+}
+
+func (s *googleEventService) Get(ctx context.Context, calendarID string, eventID string) (*gcalendar.Event, error) {
+	return s.events.Get(calendarID, eventID).Context(ctx).Do()
+}
+
+func (s *googleEventService) FindByProperty(ctx context.Context, calendarID string, key string, value string) ([]*gcalendar.Event, error) {
+	found, err := s.events.List(calendarID).
+		PrivateExtendedProperty(fmt.Sprintf("%s=%s", key, value)).
+		ShowDeleted(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+	return found.Items, nil
+}
+
+func (s *googleEventService) Insert(ctx context.Context, calendarID string, event *gcalendar.Event) (*gcalendar.Event, error) {
+	return s.events.Insert(calendarID, event).Context(ctx).Do()
+}
+
+func (s *googleEventService) Update(ctx context.Context, calendarID string, eventID string, event *gcalendar.Event) (*gcalendar.Event, error) {
+	return s.events.Update(calendarID, eventID, event).Context(ctx).Do()
+}
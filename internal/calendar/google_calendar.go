@@ -20,7 +20,7 @@ import (
 
 type Google struct {
 	client       *gcalendar.Service
-	eventService *gcalendar.EventsService
+	eventService eventService
 
 	calendarID string
 }
@@ -37,43 +37,257 @@ func NewGoogleCalendar(ctx context.Context, apiKey string, calendarID string) (*
 	}
 	return &Google{
 		client:       client,
-		eventService: gcalendar.NewEventsService(client),
+		eventService: &googleEventService{events: gcalendar.NewEventsService(client)},
 		calendarID:   calendarID,
 	}, nil
 }
 
-func (g *Google) CreateEvent(ctx context.Context, event *events.Event) error {
-	googleValidID := base32.HexEncoding.EncodeToString([]byte(event.ID))
-	googleValidID = strings.ToLower(strings.TrimRight(googleValidID, "="))
+// StatusChange records an event whose status is different from what we previously published to the calendar
+type StatusChange struct {
+	Event          *events.Event
+	PreviousStatus events.EventStatus
+}
+
+const (
+	statusPropertyKey  = "status"
+	eventIDPropertyKey = "event_id"
+)
+
+// googleEventID builds the calendar ID from the event's stable key rather than its source ID, so the same game coming
+// from two sources (or a source changing its IDs) still ends up as a single calendar entry
+func googleEventID(event *events.Event) string {
+	return encodeGoogleID(event.Key())
+}
+
+// legacyGoogleEventID is the calendar ID we used before event IDs were prefixed with their source, which was built from
+// the source's own ID. Entries published back then don't have the event ID property either.
+func legacyGoogleEventID(event *events.Event) string {
+	_, rawID, found := strings.Cut(event.ID, ":")
+	if !found {
+		rawID = event.ID
+	}
+	return encodeGoogleID(rawID)
+}
+
+func encodeGoogleID(id string) string {
+	googleValidID := base32.HexEncoding.EncodeToString([]byte(id))
+	return strings.ToLower(strings.TrimRight(googleValidID, "="))
+}
+
+// getByID returns the calendar entry with the given ID, or nil if there isn't one
+func (g *Google) getByID(ctx context.Context, googleID string) (*gcalendar.Event, error) {
+	existing, err := g.eventService.Get(ctx, g.calendarID, googleID)
+	if gerr, ok := errors.AsType[*googleapi.Error](err); ok && gerr.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	return existing, err
+}
+
+// findExisting looks for the calendar entry we previously published for the event. We first look it up by the ID built
+// from the event's key. If the event was rescheduled, its key changed, so we then fall back to searching for the
+// source's event ID, which we store on every entry. Entries from before we did either only have the legacy ID, so that's
+// the last thing we try. It returns nil if we have never published the event.
+func (g *Google) findExisting(ctx context.Context, event *events.Event) (*gcalendar.Event, error) {
+	googleID := googleEventID(event)
+	existing, err := g.getByID(ctx, googleID)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	found, err := g.eventService.FindByProperty(ctx, g.calendarID, eventIDPropertyKey, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) > 0 {
+		log.Info().Ctx(ctx).Str("event_id", event.ID).Str("existing_google_id", found[0].Id).Str("google_id", googleID).Msg("found moved event by source ID")
+		return found[0], nil
+	}
+
+	legacyID := legacyGoogleEventID(event)
+	existing, err = g.getByID(ctx, legacyID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	log.Info().Ctx(ctx).Str("event_id", event.ID).Str("existing_google_id", legacyID).Str("google_id", googleID).Msg("found event by legacy ID")
+	return existing, nil
+}
+
+// SyncAction is what syncing an event does to the calendar
+type SyncAction string
+
+const (
+	SyncInsert SyncAction = "insert"
+	SyncUpdate SyncAction = "update"
+	// SyncDelete cancels an entry we published before. Google treats cancelled entries as deleted.
+	SyncDelete SyncAction = "delete"
+	// SyncUnchanged is for entries that already match the event, which are left alone
+	SyncUnchanged SyncAction = "unchanged"
+	// SyncSkip is for cancelled events we never published, which are left out of the calendar
+	SyncSkip SyncAction = "skip"
+)
+
+// PlannedSync is what SyncEvent would do for an event
+type PlannedSync struct {
+	Event    *events.Event
+	Action   SyncAction
+	GoogleID string
+
+	// StatusChange is set if the event's status is different from what we published before
+	StatusChange *StatusChange
+}
+
+// googleEventFor builds the calendar entry for the event
+func googleEventFor(event *events.Event) *gcalendar.Event {
+	status := event.Status.Normalized()
+
+	googleStatus := "confirmed"
+	if status == events.StatusCancelled {
+		googleStatus = "cancelled"
+	}
 
-	// for now, assume every event is 3 hours...but we could probably do better
-	start := time.Unix(event.RawTime, 0)
-	end := start.Add(3 * time.Hour)
+	var source *gcalendar.EventSource
+	if link := event.PrimaryLink(); link != nil {
+		source = &gcalendar.EventSource{
+			Title: link.Label(),
+			Url:   link.URL,
+		}
+	}
 
-	googleEvent := gcalendar.Event{
-		Id:          googleValidID,
-		Description: event.String(),
-		Summary:     event.CalendarSummary(),
+	return &gcalendar.Event{
+		Id:          googleEventID(event),
+		Description: event.CalendarDescription(),
+		Source:      source,
+		Summary:     event.CalendarTitle(),
 		Location:    event.Venue,
-		Start:       &gcalendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
-		End:         &gcalendar.EventDateTime{DateTime: end.Format(time.RFC3339)},
+		Status:      googleStatus,
+		Start:       &gcalendar.EventDateTime{DateTime: event.StartTime().Format(time.RFC3339)},
+		End:         &gcalendar.EventDateTime{DateTime: event.EndTime().Format(time.RFC3339)},
+		ExtendedProperties: &gcalendar.EventExtendedProperties{
+			Private: map[string]string{
+				statusPropertyKey:  string(status),
+				eventIDPropertyKey: event.ID,
+			},
+		},
 	}
-	createdEvent, err := g.eventService.Insert(g.calendarID, &googleEvent).Context(ctx).Do()
-	if gerr, ok := errors.AsType[*googleapi.Error](err); ok && gerr.Code == http.StatusConflict {
-		// event already exists, so try to update
-		log.Warn().Ctx(ctx).Str("event_id", googleValidID).Msg("event already exists, attempting to update")
-		createdEvent, err = g.eventService.Update(g.calendarID, googleValidID, &googleEvent).Context(ctx).Do()
+}
+
+func sameTime(a *gcalendar.EventDateTime, b *gcalendar.EventDateTime) bool {
+	if a == nil || b == nil {
+		return a == b
 	}
-	if err != nil && !googleapi.IsNotModified(err) {
-		log.Error().Ctx(ctx).Err(err).Str("event_description", event.String()).Msg("could not insert event")
-		return fmt.Errorf("calendar: CreateEvent: could not create event: %w", err)
+	at, aErr := time.Parse(time.RFC3339, a.DateTime)
+	bt, bErr := time.Parse(time.RFC3339, b.DateTime)
+	return aErr == nil && bErr == nil && at.Equal(bt)
+}
+
+// sameEntry is whether updating existing to wanted would change anything people can see
+func sameEntry(existing *gcalendar.Event, wanted *gcalendar.Event) bool {
+	return existing.Summary == wanted.Summary &&
+		existing.Description == wanted.Description &&
+		existing.Location == wanted.Location &&
+		existing.Status == wanted.Status &&
+		sameTime(existing.Start, wanted.Start) &&
+		sameTime(existing.End, wanted.End)
+}
+
+// plan looks up what we previously published for the event and decides what syncing it does. It returns the entry to
+// write along with the plan, and doesn't change anything in the calendar.
+func (g *Google) plan(ctx context.Context, event *events.Event) (*PlannedSync, *gcalendar.Event, error) {
+	googleEvent := googleEventFor(event)
+	status := event.Status.Normalized()
+
+	planned := &PlannedSync{
+		Event:    event,
+		GoogleID: googleEvent.Id,
+	}
+
+	existing, err := g.findExisting(ctx, event)
+	if err != nil {
+		log.Error().Ctx(ctx).Err(err).Str("event_id", googleEvent.Id).Msg("could not look up existing event")
+		return nil, nil, fmt.Errorf("could not look up event: %w", err)
+	}
+
+	if existing == nil {
+		planned.Action = SyncInsert
+		if status == events.StatusCancelled {
+			planned.Action = SyncSkip
+		}
+		return planned, googleEvent, nil
+	}
+
+	// events that were in the calendar before we tracked status were all published as scheduled
+	previousStatus := events.StatusScheduled
+	if existing.ExtendedProperties != nil && existing.ExtendedProperties.Private[statusPropertyKey] != "" {
+		previousStatus = events.EventStatus(existing.ExtendedProperties.Private[statusPropertyKey])
+	}
+
+	// if the event was found by its source ID, it has moved, so keep the ID of the entry we already have and update it
+	// in place
+	googleEvent.Id = existing.Id
+	planned.GoogleID = existing.Id
+
+	switch {
+	case sameEntry(existing, googleEvent):
+		planned.Action = SyncUnchanged
+	case status == events.StatusCancelled:
+		planned.Action = SyncDelete
+	default:
+		planned.Action = SyncUpdate
 	}
 
-	if googleapi.IsNotModified(err) {
-		log.Warn().Ctx(ctx).Str("event_id", googleValidID).Str("event_description", event.String()).Msg("event already existed in google calendar")
-		return nil
+	if previousStatus != status {
+		planned.StatusChange = &StatusChange{
+			Event:          event,
+			PreviousStatus: previousStatus,
+		}
+	}
+
+	return planned, googleEvent, nil
+}
+
+// PlanSync returns what SyncEvent would do for the event, without changing anything in the calendar
+func (g *Google) PlanSync(ctx context.Context, event *events.Event) (*PlannedSync, error) {
+	planned, _, err := g.plan(ctx, event)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: PlanSync: %w", err)
+	}
+	return planned, nil
+}
+
+// SyncEvent creates or updates the calendar entry for the given event. Cancelled events get cancelled in the calendar
+// and rescheduled events get moved to their new time. If we had previously published the event with a different
+// status, the change is returned so the caller can let people know.
+func (g *Google) SyncEvent(ctx context.Context, event *events.Event) (*StatusChange, error) {
+	planned, googleEvent, err := g.plan(ctx, event)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: SyncEvent: %w", err)
+	}
+
+	switch planned.Action {
+	case SyncSkip:
+		log.Info().Ctx(ctx).Str("event_id", planned.GoogleID).Str("event_description", event.String()).Msg("event is cancelled and was never published, not adding to google calendar")
+		return nil, nil
+	case SyncInsert:
+		createdEvent, err := g.eventService.Insert(ctx, g.calendarID, googleEvent)
+		if err != nil {
+			log.Error().Ctx(ctx).Err(err).Str("event_description", event.String()).Msg("could not insert event")
+			return nil, fmt.Errorf("calendar: SyncEvent: could not create event: %w", err)
+		}
+
+		log.Info().Ctx(ctx).Str("event_id", createdEvent.Id).Str("event_description", event.String()).Msg("event created in google calendar")
+		return nil, nil
+	case SyncUnchanged:
+		log.Info().Ctx(ctx).Str("event_id", planned.GoogleID).Str("event_description", event.String()).Msg("event already up to date in google calendar")
+		return planned.StatusChange, nil
+	}
+
+	log.Info().Ctx(ctx).Str("event_id", planned.GoogleID).Str("action", string(planned.Action)).Str("status", string(event.Status.Normalized())).Msg("event already exists, updating")
+	_, err = g.eventService.Update(ctx, g.calendarID, planned.GoogleID, googleEvent)
+	if err != nil && !googleapi.IsNotModified(err) {
+		log.Error().Ctx(ctx).Err(err).Str("event_description", event.String()).Msg("could not update event")
+		return nil, fmt.Errorf("calendar: SyncEvent: could not update event: %w", err)
 	}
 
-	log.Info().Ctx(ctx).Str("event_id", createdEvent.Id).Str("event_description", event.String()).Msg("event created in google calendar")
-	return nil
+	return planned.StatusChange, nil
 }
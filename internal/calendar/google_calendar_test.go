@@ -0,0 +1,165 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gcalendar "google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func TestSameEntry(t *testing.T) {
+	event := &events.Event{
+		TeamName:  "Seattle Mariners",
+		Opponent:  "Houston Astros",
+		Venue:     "T-Mobile Park",
+		LocalTime: "7:10 PM",
+		RawTime:   time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone).Unix(),
+	}
+	wanted := googleEventFor(event)
+
+	// google gives times back in UTC, which is still the same time
+	existing := *wanted
+	existing.Start = &gcalendar.EventDateTime{DateTime: event.StartTime().UTC().Format(time.RFC3339)}
+	assert.True(t, sameEntry(&existing, wanted))
+
+	existing.Location = "Safeco Field"
+	assert.False(t, sameEntry(&existing, wanted))
+
+	event.Status = events.StatusCancelled
+	assert.False(t, sameEntry(wanted, googleEventFor(event)))
+}
+
+// fakeEventService keeps a calendar's entries in a map
+type fakeEventService struct {
+	entries map[string]*gcalendar.Event
+	inserts []string
+	updates []string
+}
+
+func newFakeEventService(entries ...*gcalendar.Event) *fakeEventService {
+	f := &fakeEventService{entries: map[string]*gcalendar.Event{}}
+	for _, curr := range entries {
+		f.entries[curr.Id] = curr
+	}
+	return f
+}
+
+func (f *fakeEventService) Get(_ context.Context, _ string, eventID string) (*gcalendar.Event, error) {
+	existing, ok := f.entries[eventID]
+	if !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	return existing, nil
+}
+
+func (f *fakeEventService) FindByProperty(_ context.Context, _ string, key string, value string) ([]*gcalendar.Event, error) {
+	var found []*gcalendar.Event
+	for _, curr := range f.entries {
+		if curr.ExtendedProperties != nil && curr.ExtendedProperties.Private[key] == value {
+			found = append(found, curr)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeEventService) Insert(_ context.Context, _ string, event *gcalendar.Event) (*gcalendar.Event, error) {
+	if _, ok := f.entries[event.Id]; ok {
+		return nil, &googleapi.Error{Code: http.StatusConflict}
+	}
+	f.entries[event.Id] = event
+	f.inserts = append(f.inserts, event.Id)
+	return event, nil
+}
+
+func (f *fakeEventService) Update(_ context.Context, _ string, eventID string, event *gcalendar.Event) (*gcalendar.Event, error) {
+	if _, ok := f.entries[eventID]; !ok {
+		return nil, &googleapi.Error{Code: http.StatusNotFound}
+	}
+	f.entries[eventID] = event
+	f.updates = append(f.updates, eventID)
+	return event, nil
+}
+
+func testGame(start time.Time) *events.Event {
+	return &events.Event{
+		ID:        "espn:401234",
+		TeamName:  "Seattle Mariners",
+		Opponent:  "Houston Astros",
+		Venue:     "T-Mobile Park",
+		LocalTime: start.Format("3:04 PM"),
+		RawTime:   start.Unix(),
+	}
+}
+
+func TestSyncEvent(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	t.Run("new event", func(t *testing.T) {
+		service := newFakeEventService()
+		g := &Google{eventService: service}
+
+		change, err := g.SyncEvent(ctx, testGame(start))
+		require.NoError(t, err)
+		assert.Nil(t, change)
+		assert.Equal(t, []string{googleEventID(testGame(start))}, service.inserts)
+	})
+
+	t.Run("published before event IDs had sources", func(t *testing.T) {
+		// the entry only has the ID built from ESPN's own ID, and no event ID property
+		legacyID := encodeGoogleID("401234")
+		service := newFakeEventService(&gcalendar.Event{Id: legacyID, Summary: "Seattle Mariners vs Houston Astros", Status: "confirmed"})
+		g := &Google{eventService: service}
+
+		planned, err := g.PlanSync(ctx, testGame(start))
+		require.NoError(t, err)
+		assert.Equal(t, SyncUpdate, planned.Action)
+		assert.Equal(t, legacyID, planned.GoogleID)
+
+		_, err = g.SyncEvent(ctx, testGame(start))
+		require.NoError(t, err)
+		assert.Empty(t, service.inserts)
+		assert.Equal(t, []string{legacyID}, service.updates)
+		assert.Len(t, service.entries, 1)
+
+		// now that it has the event ID property, it's found again even after it moves
+		moved := testGame(start.Add(time.Hour))
+		_, err = g.SyncEvent(ctx, moved)
+		require.NoError(t, err)
+		assert.Empty(t, service.inserts)
+		assert.Equal(t, []string{legacyID, legacyID}, service.updates)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		service := newFakeEventService(googleEventFor(testGame(start)))
+		g := &Google{eventService: service}
+
+		planned, err := g.PlanSync(ctx, testGame(start))
+		require.NoError(t, err)
+		assert.Equal(t, SyncUnchanged, planned.Action)
+
+		change, err := g.SyncEvent(ctx, testGame(start))
+		require.NoError(t, err)
+		assert.Nil(t, change)
+		assert.Empty(t, service.inserts)
+		assert.Empty(t, service.updates)
+	})
+
+	t.Run("cancelled and never published", func(t *testing.T) {
+		service := newFakeEventService()
+		g := &Google{eventService: service}
+
+		cancelled := testGame(start)
+		cancelled.Status = events.StatusCancelled
+		_, err := g.SyncEvent(ctx, cancelled)
+		require.NoError(t, err)
+		assert.Empty(t, service.inserts)
+	})
+}
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -16,6 +19,10 @@ var (
 	testDate        string
 	uploadAnyway    bool
 	invalidateCache bool
+	outputFormat    string
+	publishDir      string
+	dryRun          bool
+	rollbackTo      string
 
 	rootCmd *urfavecli.Command
 )
@@ -42,6 +49,28 @@ func init() {
 				Usage:       "invalidate everything in cloudfront cache (requires upload anyway)",
 				Destination: &invalidateCache,
 			},
+			&urfavecli.StringFlag{
+				Name:        "format",
+				Usage:       fmt.Sprintf("what to print when running locally (one of %s; defaults to both json and html)", strings.Join(handler.OutputFormats, ", ")),
+				Destination: &outputFormat,
+				Validator: func(s string) error {
+					if !slices.Contains(handler.OutputFormats, s) {
+						return fmt.Errorf("unknown format %q", s)
+					}
+					return nil
+				},
+			},
+			&urfavecli.StringFlag{
+				Name:        "publish-dir",
+				Usage:       "publish the whole site to this directory instead of printing it (or uploading it)",
+				Destination: &publishDir,
+			},
+			&urfavecli.BoolFlag{
+				Name:        "dry-run",
+				Value:       false,
+				Usage:       "compare what would be published with what is live in S3 (or the publish dir), and what would be synced to the google calendar, without changing anything",
+				Destination: &dryRun,
+			},
 			&urfavecli.StringFlag{
 				Name:        "date",
 				Value:       time.Now().In(seattleTimeZone).Format("2006-01-02"),
@@ -49,8 +78,47 @@ func init() {
 				Destination: &testDate,
 			},
 		},
-		Action: func(ctx context.Context, command *urfavecli.Command) error {
+		Before: func(ctx context.Context, command *urfavecli.Command) (context.Context, error) {
 			log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+			return ctx, nil
+		},
+		Commands: []*urfavecli.Command{
+			{
+				Name:  "rollback",
+				Usage: "make a previous run's release live again",
+				Flags: []urfavecli.Flag{
+					&urfavecli.StringFlag{
+						Name:        "to",
+						Usage:       "run ID of the release to roll back to (see the releases command)",
+						Required:    true,
+						Destination: &rollbackTo,
+					},
+				},
+				Action: func(ctx context.Context, command *urfavecli.Command) error {
+					return handler.Rollback(ctx, rollbackTo, publishDir)
+				},
+			},
+			{
+				Name:  "releases",
+				Usage: "list the releases that can be rolled back to",
+				Action: func(ctx context.Context, command *urfavecli.Command) error {
+					index, err := handler.Releases(ctx, publishDir)
+					if err != nil {
+						return err
+					}
+
+					for _, curr := range index.Releases {
+						marker := " "
+						if curr.ID == index.Current {
+							marker = "*"
+						}
+						fmt.Printf("%s %s  %s\n", marker, curr.ID, curr.CreatedAt.Local().Format(time.RFC1123))
+					}
+					return nil
+				},
+			},
+		},
+		Action: func(ctx context.Context, command *urfavecli.Command) error {
 			ce := handler.CustomEvent{}
 			ce.Today = testDate
 			if uploadAnyway {
@@ -59,6 +127,9 @@ func init() {
 			if invalidateCache {
 				ce.InvalidateAll = true
 			}
+			ce.Format = outputFormat
+			ce.PublishDir = publishDir
+			ce.DryRun = dryRun
 			err := handler.EventHandler(ctx, ce)
 			if err != nil {
 				return err
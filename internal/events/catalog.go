@@ -0,0 +1,155 @@
+package events
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Venue is a Seattle venue that we track events at
+type Venue struct {
+	Name string
+	Slug string
+
+	// Aliases are other names that sources use for this venue. This includes old names (sources are slow to catch up
+	// with naming rights deals) and longer or shorter forms of the current name.
+	Aliases []string
+
+	Address Address
+}
+
+// Address is a venue's street address
+type Address struct {
+	Street     string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+}
+
+func seattleAddress(street string, postalCode string) Address {
+	return Address{
+		Street:     street,
+		City:       "Seattle",
+		Region:     "WA",
+		PostalCode: postalCode,
+		Country:    "US",
+	}
+}
+
+// Venues is the catalog of venues we know about. Anything not in here is not in Seattle as far as we're concerned.
+var Venues = []*Venue{
+	{
+		Name:    "Climate Pledge Arena",
+		Slug:    "climate-pledge-arena",
+		Aliases: []string{"KeyArena", "Key Arena", "Seattle Center Coliseum"},
+		Address: seattleAddress("334 1st Ave N", "98109"),
+	},
+	{
+		Name:    "Lumen Field",
+		Slug:    "lumen-field",
+		Aliases: []string{"CenturyLink Field", "Qwest Field", "Seahawks Stadium", "Seattle Stadium"},
+		Address: seattleAddress("800 Occidental Ave S", "98134"),
+	},
+	{
+		Name:    "T-Mobile Park",
+		Slug:    "t-mobile-park",
+		Aliases: []string{"Safeco Field"},
+		Address: seattleAddress("1250 1st Ave S", "98134"),
+	},
+	{
+		Name:    "WAMU Theater",
+		Slug:    "wamu-theater",
+		Aliases: []string{"Lumen Field Event Center"},
+		Address: seattleAddress("800 Occidental Ave S", "98134"),
+	},
+	{
+		Name:    huskyStadium,
+		Slug:    "husky-stadium",
+		Aliases: []string{"Alaska Airlines Field at Husky Stadium"},
+		Address: seattleAddress("3800 Montlake Blvd NE", "98195"),
+	},
+	{
+		Name:    alaskaAirlinesArena,
+		Slug:    "alaska-airlines-arena",
+		Aliases: []string{"Alaska Airlines Arena at Hec Edmundson Pavilion", "Hec Edmundson Pavilion"},
+		Address: seattleAddress("3870 Montlake Blvd NE", "98195"),
+	},
+}
+
+// Team is a Seattle team that we track home games for
+type Team struct {
+	// Name is the name of the team as it shows up in Event.TeamName
+	Name   string
+	Slug   string
+	League string
+
+	// ReportsAwayGames is set if the team's source lists games that aren't home games too (like the Huskies at Climate
+	// Pledge), so only games it says are at home count. Every other source only reports home games.
+	ReportsAwayGames bool
+}
+
+// Teams is the catalog of teams we know about
+var Teams = []*Team{
+	{Name: "Seattle Mariners", Slug: "mariners", League: "MLB"},
+	{Name: "Seattle Sounders", Slug: "sounders", League: "MLS"},
+	{Name: "Seattle Kraken", Slug: "kraken", League: "NHL"},
+	{Name: "Seattle Torrent", Slug: "torrent", League: "PWHL"},
+	{Name: "Seattle Seahawks", Slug: "seahawks", League: "NFL"},
+	{Name: "Seattle Storm", Slug: "storm", League: "WNBA"},
+	{Name: "Seattle Reign", Slug: "reign", League: "NWSL"},
+	{Name: huskiesFootballName, Slug: "huskies-football", League: "NCAA", ReportsAwayGames: true},
+	{Name: huskiesMensBasketballName, Slug: "huskies-mens-basketball", League: "NCAA", ReportsAwayGames: true},
+	{Name: huskiesWomensBasketballName, Slug: "huskies-womens-basketball", League: "NCAA", ReportsAwayGames: true},
+}
+
+var venuesByNormalizedName map[string]*Venue
+
+func init() {
+	venuesByNormalizedName = map[string]*Venue{}
+	for _, curr := range Venues {
+		venuesByNormalizedName[normalizeVenueName(curr.Name)] = curr
+		for _, alias := range curr.Aliases {
+			venuesByNormalizedName[normalizeVenueName(alias)] = curr
+		}
+	}
+}
+
+// normalizeVenueName lower cases the name and strips out everything that isn't a letter or a number so that things
+// like "T-Mobile Park" and "TMobile Park" or "WaMu Theater" and "WAMU Theater" match
+func normalizeVenueName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// IsHomeGameFor returns true if the event is a home game for the given team. Away and neutral site games never are,
+// and games whose side we don't know only count for teams whose source only reports home games.
+func (e *Event) IsHomeGameFor(t *Team) bool {
+	if e.TeamName != t.Name {
+		return false
+	}
+
+	switch e.HomeAway {
+	case HomeAwayHome:
+		return true
+	case HomeAwayAway, HomeAwayNeutral:
+		return false
+	default:
+		return !t.ReportsAwayGames
+	}
+}
+
+// IsAt returns true if the event is at the given venue
+func (e *Event) IsAt(v *Venue) bool {
+	return LookupVenue(e.Venue) == v
+}
+
+// LookupVenue finds the venue in the catalog with the given name or alias. It returns nil if the venue isn't one of
+// ours.
+func LookupVenue(name string) *Venue {
+	return venuesByNormalizedName[normalizeVenueName(name)]
+}
@@ -29,6 +29,10 @@ var (
 
 const (
 	localTimeDateFormat = "3:04 PM"
+
+	// DefaultEventDuration is how long we assume every event lasts, since none of our sources tell us when things end.
+	// We could probably do better.
+	DefaultEventDuration = 3 * time.Hour
 )
 
 func init() {
@@ -51,6 +55,22 @@ type EventResults struct {
 	TomorrowEvents []*Event
 }
 
+// Filter returns a copy of the results with only the events that keep returns true for
+func (r *EventResults) Filter(keep func(*Event) bool) *EventResults {
+	filtered := &EventResults{}
+	for _, curr := range r.TodayEvent {
+		if keep(curr) {
+			filtered.TodayEvent = append(filtered.TodayEvent, curr)
+		}
+	}
+	for _, curr := range r.TomorrowEvents {
+		if keep(curr) {
+			filtered.TomorrowEvents = append(filtered.TomorrowEvents, curr)
+		}
+	}
+	return filtered
+}
+
 type Event struct {
 	ID               string `json:"id"`
 	TeamName         string `json:"team_name"`
@@ -59,9 +79,149 @@ type Event struct {
 	Opponent         string `json:"opponent"`
 	ShortDescription string `json:"short_description"`
 
+	// Name is the name of the event for events that aren't a Seattle team's game (e.g. the name of the concert). Sources
+	// that only give us a prewritten description leave it empty.
+	Name string `json:"name,omitempty"`
+
 	RawDescription string `json:"raw_description,omitempty"`
 
 	RawTime int64 `json:"raw_time"`
+
+	// Session is set when this event is one of several linked sessions (a doubleheader or a multi-day event)
+	Session *Session `json:"session,omitempty"`
+
+	Status   EventStatus   `json:"status,omitempty"`
+	Category EventCategory `json:"category,omitempty"`
+
+	// HomeAway is whether the Seattle team is the home or away team, or if the game is at a neutral site. It is empty
+	// if the source doesn't tell us (or the event isn't a game).
+	HomeAway HomeAway `json:"home_away,omitempty"`
+
+	Links    []Link `json:"links,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type LinkKind string
+
+const (
+	LinkKindTickets LinkKind = "tickets"
+	LinkKindTeam    LinkKind = "team"
+	LinkKindInfo    LinkKind = "info"
+)
+
+// Link is an outbound link for an event (where to buy tickets, the team's home page, etc.)
+type Link struct {
+	Kind LinkKind `json:"kind"`
+	URL  string   `json:"url"`
+}
+
+// Label is the text we put on the link when rendering it
+func (l Link) Label() string {
+	switch l.Kind {
+	case LinkKindTickets:
+		return "Tickets"
+	case LinkKindTeam:
+		return "Team page"
+	default:
+		return "More info"
+	}
+}
+
+// PrimaryLink returns the single most useful link for the event, preferring tickets, then event info, then the team
+// page. It returns nil if the event has no links.
+func (e *Event) PrimaryLink() *Link {
+	for _, kind := range []LinkKind{LinkKindTickets, LinkKindInfo, LinkKindTeam} {
+		for i := range e.Links {
+			if e.Links[i].Kind == kind {
+				return &e.Links[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// addLink appends a link if the URL is not empty, since most of our sources only sometimes have them
+func (e *Event) addLink(kind LinkKind, url string) {
+	if url == "" {
+		return
+	}
+	e.Links = append(e.Links, Link{Kind: kind, URL: url})
+}
+
+type EventStatus string
+
+const (
+	StatusScheduled   EventStatus = "scheduled"
+	StatusPostponed   EventStatus = "postponed"
+	StatusRescheduled EventStatus = "rescheduled"
+	StatusCancelled   EventStatus = "cancelled"
+)
+
+// Normalized returns the status with the empty value (which is what all the sources give us when everything is fine)
+// treated as scheduled
+func (s EventStatus) Normalized() EventStatus {
+	if s == "" {
+		return StatusScheduled
+	}
+	return s
+}
+
+type EventCategory string
+
+const (
+	CategorySports EventCategory = "sports"
+	CategoryMusic  EventCategory = "music"
+	CategoryOther  EventCategory = "other"
+)
+
+// HomeAway is which side the Seattle team is on in a game
+type HomeAway string
+
+const (
+	HomeAwayHome    HomeAway = "home"
+	HomeAwayAway    HomeAway = "away"
+	HomeAwayNeutral HomeAway = "neutral"
+)
+
+// Normalized returns the category with the empty value (which is what we get when a source doesn't tell us) treated as
+// other
+func (c EventCategory) Normalized() EventCategory {
+	if c == "" {
+		return CategoryOther
+	}
+	return c
+}
+
+// IsHappening returns false if the event is not going to take place at the listed time
+func (e *Event) IsHappening() bool {
+	return e.Status != StatusPostponed && e.Status != StatusCancelled
+}
+
+// StatusLabel is the badge text shown next to an event that isn't going ahead as originally scheduled. It is empty
+// for normal events.
+func (e *Event) StatusLabel() string {
+	switch e.Status {
+	case StatusPostponed:
+		return "POSTPONED"
+	case StatusRescheduled:
+		return "RESCHEDULED"
+	case StatusCancelled:
+		return "CANCELLED"
+	default:
+		return ""
+	}
+}
+
+// AnyHappening returns true if at least one of the events is going ahead. A day where the only game got postponed is
+// not a game day.
+func AnyHappening(x []*Event) bool {
+	for _, curr := range x {
+		if curr.IsHappening() {
+			return true
+		}
+	}
+	return false
 }
 
 func (e *Event) CalendarSummary() string {
@@ -73,14 +233,51 @@ func (e *Event) CalendarSummary() string {
 		return e.RawDescription
 	}
 
+	if e.Session.isDoubleheader() {
+		return fmt.Sprintf("%s are playing against the %s at %s (game %d of %d)", e.TeamName, e.Opponent, e.Venue, e.Session.Number, e.Session.Count)
+	}
+
 	return fmt.Sprintf("%s are playing against the %s at %s", e.TeamName, e.Opponent, e.Venue)
 }
 
+// StartTime is when the event starts, in Seattle time
+func (e *Event) StartTime() time.Time {
+	return time.Unix(e.RawTime, 0).In(SeattleTimeZone)
+}
+
+// EndTime is when we think the event ends (see DefaultEventDuration)
+func (e *Event) EndTime() time.Time {
+	return e.StartTime().Add(DefaultEventDuration)
+}
+
+// CalendarTitle is the CalendarSummary with the status label in front for events that aren't going ahead as planned
+func (e *Event) CalendarTitle() string {
+	if label := e.StatusLabel(); label != "" {
+		return fmt.Sprintf("%s: %s", label, e.CalendarSummary())
+	}
+	return e.CalendarSummary()
+}
+
+// CalendarDescription is the String() with the event's primary link (if any) on the end
+func (e *Event) CalendarDescription() string {
+	if link := e.PrimaryLink(); link != nil {
+		return fmt.Sprintf("%s\n\n%s: %s", e.String(), link.Label(), link.URL)
+	}
+	return e.String()
+}
+
 func (e *Event) String() string {
 	if e.RawDescription != "" {
 		return e.RawDescription
 	}
 
+	if e.Session.isDoubleheader() {
+		if e.Session.Number == 1 {
+			return fmt.Sprintf("%s are playing a doubleheader against the %s at %s starting at %s.", e.TeamName, e.Opponent, e.Venue, e.LocalTime)
+		}
+		return fmt.Sprintf("%s are playing game %d of a doubleheader against the %s at %s. The game starts at %s.", e.TeamName, e.Session.Number, e.Opponent, e.Venue, e.LocalTime)
+	}
+
 	return fmt.Sprintf("%s are playing against the %s at %s. The game starts at %s.", e.TeamName, e.Opponent, e.Venue, e.LocalTime)
 }
 
@@ -133,6 +330,8 @@ func GetTodayAndTomorrowGames(ctx context.Context, seattleToday time.Time, seatt
 			limiter:       rate.NewLimiter(3, 1),
 			apiKey:        apiKey,
 			baseURL:       TicketmasterDefaultBaseURL,
+
+			multiDayLookbackDays: TicketmasterMultiDayLookbackDays,
 		}
 
 		err = fetchAndAppendEvents(ctx, tm.GetEvents, res, &eventLock, seattleToday, seattleTomorrow)
@@ -157,5 +356,11 @@ func GetTodayAndTomorrowGames(ctx context.Context, seattleToday time.Time, seatt
 
 	wg.Wait()
 
+	res.TodayEvent = dedupe(res.TodayEvent)
+	res.TomorrowEvents = dedupe(res.TomorrowEvents)
+
+	linkDoubleheaders(res.TodayEvent)
+	linkDoubleheaders(res.TomorrowEvents)
+
 	return res, errors.Join(errs...)
 }
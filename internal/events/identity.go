@@ -0,0 +1,145 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Every event ID is prefixed with the source it came from, so IDs from different sources can never collide
+const (
+	SourceTicketmaster = "ticketmaster"
+	SourceESPN         = "espn"
+	SourceSpecial      = "special"
+)
+
+// sourcePriority decides which copy of an event we keep when more than one source reports it. Special events are
+// entered by hand, so they always win. ESPN is better than ticketmaster about game status.
+var sourcePriority = map[string]int{
+	SourceSpecial:      0,
+	SourceESPN:         1,
+	SourceTicketmaster: 2,
+}
+
+func sourceID(source string, id string) string {
+	return fmt.Sprintf("%s:%s", source, id)
+}
+
+// Source returns the name of the source that this event came from
+func (e *Event) Source() string {
+	source, _, found := strings.Cut(e.ID, ":")
+	if !found {
+		return ""
+	}
+	return source
+}
+
+// Key is a stable identity for the real-world event, built from the venue, date and start time. Unlike ID, it is the
+// same no matter which source we got the event from and survives a source changing its IDs. Events with a TBA time all
+// have the same made up start time, so their name stands in for it.
+func (e *Event) Key() string {
+	venue := e.Venue
+	if v := LookupVenue(e.Venue); v != nil {
+		venue = v.Slug
+	} else {
+		venue = slugify(venue)
+	}
+
+	if venue == "" {
+		// without a venue, we don't have anything better than what the source gave us
+		return e.ID
+	}
+
+	start := time.Unix(e.RawTime, 0).In(SeattleTimeZone)
+	if e.LocalTime == "TBA" {
+		return fmt.Sprintf("%s/%s/tba-%s", venue, start.Format("2006-01-02"), e.nameSlug())
+	}
+	return fmt.Sprintf("%s/%s/%s", venue, start.Format("2006-01-02"), start.Format("1504"))
+}
+
+// nameSlug is what the event is called, as a slug. Games are named for who is playing, since that's the same no matter
+// which source we got them from. It falls back to the ID for events that have no name at all.
+func (e *Event) nameSlug() string {
+	name := e.ShortDescription
+	if e.TeamName != "" && e.Opponent != "" {
+		name = fmt.Sprintf("%s vs %s", e.TeamName, e.Opponent)
+	} else if name == "" {
+		name = e.RawDescription
+	}
+
+	if slug := slugify(name); slug != "" {
+		return slug
+	}
+	return slugify(e.ID)
+}
+
+// slugify turns a name in to something that is safe to put in a URL or a key (e.g. "Lumen Field" becomes
+// "lumen-field")
+func slugify(name string) string {
+	var sb strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			lastDash = false
+		} else if !lastDash {
+			sb.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// dedupe drops events that more than one source reported, keeping the copy from the highest priority source. Links
+// and images that only the dropped copy had are carried over to the one we keep.
+func dedupe(x []*Event) []*Event {
+	kept := map[string]*Event{}
+	var keys []string
+
+	for _, curr := range x {
+		key := curr.Key()
+		existing, ok := kept[key]
+		if !ok {
+			kept[key] = curr
+			keys = append(keys, key)
+			continue
+		}
+
+		winner, loser := existing, curr
+		if sourcePriority[curr.Source()] < sourcePriority[existing.Source()] {
+			winner, loser = curr, existing
+		}
+
+		log.Info().Str("key", key).Str("kept_id", winner.ID).Str("dropped_id", loser.ID).Msg("dropping duplicate event")
+
+		for _, link := range loser.Links {
+			if !winner.hasLinkKind(link.Kind) {
+				winner.Links = append(winner.Links, link)
+			}
+		}
+		if winner.ImageURL == "" {
+			winner.ImageURL = loser.ImageURL
+		}
+
+		kept[key] = winner
+	}
+
+	deduped := make([]*Event, len(keys))
+	for i, key := range keys {
+		deduped[i] = kept[key]
+	}
+
+	return deduped
+}
+
+func (e *Event) hasLinkKind(kind LinkKind) bool {
+	for _, curr := range e.Links {
+		if curr.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
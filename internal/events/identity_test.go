@@ -0,0 +1,57 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventKey(t *testing.T) {
+	start := time.Date(2026, time.April, 3, 19, 10, 0, 0, SeattleTimeZone).Unix()
+
+	assert.Equal(t, "t-mobile-park/2026-04-03/1910", (&Event{ID: "espn:1", Venue: "Safeco Field", RawTime: start}).Key())
+	assert.Equal(t, "t-mobile-park/2026-04-03/1910", (&Event{ID: "ticketmaster:abc", Venue: "T-Mobile Park", RawTime: start}).Key())
+	assert.Equal(t, "gas-works-park/2026-04-03/1910", (&Event{ID: "special:x", Venue: "Gas Works Park!", RawTime: start}).Key())
+	assert.Equal(t, "special:x", (&Event{ID: "special:x", RawTime: start}).Key())
+
+	// TBA events all get the same made up time, so they're told apart by name
+	noon := time.Date(2026, time.April, 3, 12, 0, 0, 0, SeattleTimeZone).Unix()
+	assert.Equal(t, "lumen-field/2026-04-03/tba-seattle-sounders-vs-portland-timbers", (&Event{ID: "espn:1", TeamName: "Seattle Sounders", Opponent: "Portland Timbers", Venue: "Lumen Field", LocalTime: "TBA", RawTime: noon}).Key())
+	assert.Equal(t, "lumen-field/2026-04-03/tba-some-band", (&Event{ID: "ticketmaster:abc", ShortDescription: "Some Band", Venue: "Lumen Field", LocalTime: "TBA", RawTime: noon}).Key())
+
+	assert.Equal(t, SourceTicketmaster, (&Event{ID: "ticketmaster:abc"}).Source())
+	assert.Empty(t, (&Event{ID: "abc"}).Source())
+}
+
+func TestDedupe(t *testing.T) {
+	start := time.Date(2026, time.April, 3, 19, 10, 0, 0, SeattleTimeZone).Unix()
+
+	tm := &Event{ID: "ticketmaster:abc", Venue: "T-Mobile Park", RawTime: start, Links: []Link{{Kind: LinkKindTickets, URL: "https://tickets"}}, ImageURL: "https://image"}
+	espn := &Event{ID: "espn:123", Venue: "T-Mobile Park", RawTime: start, Links: []Link{{Kind: LinkKindInfo, URL: "https://info"}}}
+	other := &Event{ID: "ticketmaster:def", Venue: "Lumen Field", RawTime: start}
+
+	deduped := dedupe([]*Event{tm, other, espn})
+	require.Len(t, deduped, 2)
+	assert.Same(t, espn, deduped[0])
+	assert.Same(t, other, deduped[1])
+
+	assert.True(t, espn.hasLinkKind(LinkKindTickets))
+	assert.True(t, espn.hasLinkKind(LinkKindInfo))
+	assert.Equal(t, "https://image", espn.ImageURL)
+}
+
+func TestDedupe_TBA(t *testing.T) {
+	noon := time.Date(2026, time.April, 3, 12, 0, 0, 0, SeattleTimeZone).Unix()
+
+	game := &Event{ID: "espn:1", TeamName: "Seattle Sounders", Opponent: "Portland Timbers", Venue: "Lumen Field", LocalTime: "TBA", RawTime: noon}
+	sameGame := &Event{ID: "ticketmaster:abc", TeamName: "Seattle Sounders", Opponent: "Portland Timbers", Venue: "Lumen Field", LocalTime: "TBA", RawTime: noon}
+	concert := &Event{ID: "ticketmaster:def", ShortDescription: "Some Band", Venue: "Lumen Field", LocalTime: "TBA", RawTime: noon}
+
+	// the concert isn't the same event just because neither has a time yet
+	deduped := dedupe([]*Event{game, concert, sameGame})
+	require.Len(t, deduped, 2)
+	assert.Same(t, game, deduped[0])
+	assert.Same(t, concert, deduped[1])
+}
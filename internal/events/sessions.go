@@ -0,0 +1,121 @@
+package events
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+type SessionKind string
+
+const (
+	SessionKindDoubleheader SessionKind = "doubleheader"
+	SessionKindMultiDay     SessionKind = "multi_day"
+)
+
+// Session links an event to the other sessions it belongs to. For a doubleheader, Number is the game number. For a
+// multi-day event, Number is the day of the event (so day 2 of 3 has Number 2 and Count 3).
+type Session struct {
+	Kind    SessionKind `json:"kind"`
+	GroupID string      `json:"group_id"`
+	Number  int         `json:"number"`
+	Count   int         `json:"count"`
+}
+
+func (s *Session) isDoubleheader() bool {
+	return s != nil && s.Kind == SessionKindDoubleheader
+}
+
+// IsContinuation returns true if this event is a later session of a group that has already been shown on the same day
+// (i.e. game 2 of a doubleheader). Multi-day events are never continuations since each day is shown on its own.
+func (e *Event) IsContinuation() bool {
+	return e.Session.isDoubleheader() && e.Session.Number > 1
+}
+
+// CollapseSessions removes the later games of doubleheaders so that pages can show a single line for the whole
+// doubleheader. The first game's String() already describes the doubleheader as a whole.
+func CollapseSessions(x []*Event) []*Event {
+	var collapsed []*Event
+	for _, curr := range x {
+		if curr.IsContinuation() {
+			continue
+		}
+		collapsed = append(collapsed, curr)
+	}
+
+	return collapsed
+}
+
+// linkDoubleheaders finds games on the same day where the same Seattle team is playing the same opponent at the same
+// venue at different times and marks them as a doubleheader. Events reported by a source more than once with the same
+// start time are not a doubleheader, so those are left alone.
+func linkDoubleheaders(x []*Event) {
+	groups := map[string][]*Event{}
+	var groupOrder []string
+
+	for _, curr := range x {
+		if curr.TeamName == "" || curr.Opponent == "" || curr.Session != nil {
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%s", curr.TeamName, curr.Opponent, curr.Venue)
+		if _, ok := groups[key]; !ok {
+			groupOrder = append(groupOrder, key)
+		}
+		groups[key] = append(groups[key], curr)
+	}
+
+	for _, key := range groupOrder {
+		games := groups[key]
+
+		startTimes := map[int64]struct{}{}
+		for _, curr := range games {
+			startTimes[curr.RawTime] = struct{}{}
+		}
+		if len(startTimes) < 2 {
+			continue
+		}
+
+		slices.SortFunc(games, func(a, b *Event) int {
+			return int(a.RawTime - b.RawTime)
+		})
+
+		groupID := fmt.Sprintf("%s-%s", time.Unix(games[0].RawTime, 0).In(SeattleTimeZone).Format("2006-01-02"), games[0].ID)
+		for i, curr := range games {
+			curr.Session = &Session{
+				Kind:    SessionKindDoubleheader,
+				GroupID: groupID,
+				Number:  i + 1,
+				Count:   len(games),
+			}
+		}
+	}
+}
+
+// multiDaySession figures out which day of a multi-day event the given day is. It returns nil if the event is not
+// running on that day.
+func multiDaySession(groupID string, start time.Time, end time.Time, day time.Time) *Session {
+	startDay := beginningOfDay(start)
+	endDay := beginningOfDay(end)
+	targetDay := beginningOfDay(day)
+
+	if targetDay.Before(startDay) || targetDay.After(endDay) {
+		return nil
+	}
+
+	return &Session{
+		Kind:    SessionKindMultiDay,
+		GroupID: groupID,
+		Number:  daysBetween(startDay, targetDay) + 1,
+		Count:   daysBetween(startDay, endDay) + 1,
+	}
+}
+
+// daysBetween counts calendar days rather than 24-hour periods so DST changes don't throw things off
+func daysBetween(a time.Time, b time.Time) int {
+	days := 0
+	for curr := a; curr.Before(b); curr = curr.AddDate(0, 0, 1) {
+		days++
+	}
+	return days
+}
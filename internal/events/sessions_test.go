@@ -0,0 +1,58 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkDoubleheaders(t *testing.T) {
+	game1 := time.Date(2026, time.June, 6, 13, 10, 0, 0, SeattleTimeZone)
+	game2 := time.Date(2026, time.June, 6, 17, 40, 0, 0, SeattleTimeZone)
+
+	x := []*Event{
+		{ID: "g2", TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "5:40 PM", RawTime: game2.Unix()},
+		{ID: "g1", TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "1:10 PM", RawTime: game1.Unix()},
+		{ID: "concert", RawDescription: "Some band is at Lumen Field", RawTime: game1.Unix()},
+		{ID: "dupe1", TeamName: "Seattle Storm", Opponent: "Las Vegas Aces", Venue: "Climate Pledge Arena", RawTime: game1.Unix()},
+		{ID: "dupe2", TeamName: "Seattle Storm", Opponent: "Las Vegas Aces", Venue: "Climate Pledge Arena", RawTime: game1.Unix()},
+	}
+
+	linkDoubleheaders(x)
+
+	require.NotNil(t, x[1].Session)
+	assert.Equal(t, SessionKindDoubleheader, x[1].Session.Kind)
+	assert.Equal(t, 1, x[1].Session.Number)
+	assert.Equal(t, 2, x[1].Session.Count)
+	require.NotNil(t, x[0].Session)
+	assert.Equal(t, 2, x[0].Session.Number)
+	assert.Equal(t, x[0].Session.GroupID, x[1].Session.GroupID)
+
+	assert.Nil(t, x[2].Session)
+	assert.Nil(t, x[3].Session, "same start time is a duplicate, not a doubleheader")
+	assert.Nil(t, x[4].Session)
+
+	assert.Equal(t, "Seattle Mariners are playing a doubleheader against the Houston Astros at T-Mobile Park starting at 1:10 PM.", x[1].String())
+	assert.Equal(t, "Seattle Mariners are playing game 2 of a doubleheader against the Houston Astros at T-Mobile Park. The game starts at 5:40 PM.", x[0].String())
+
+	collapsed := CollapseSessions(x)
+	assert.Len(t, collapsed, 4)
+	assert.NotContains(t, collapsed, x[0])
+}
+
+func TestMultiDaySession(t *testing.T) {
+	start := time.Date(2026, time.March, 7, 10, 0, 0, 0, SeattleTimeZone)
+	end := time.Date(2026, time.March, 9, 0, 0, 0, 0, SeattleTimeZone)
+
+	assert.Nil(t, multiDaySession("x", start, end, start.AddDate(0, 0, -1)))
+	assert.Nil(t, multiDaySession("x", start, end, end.AddDate(0, 0, 1)))
+
+	// DST starts on March 8th, 2026, so this also makes sure we count days and not hours
+	s := multiDaySession("x", start, end, time.Date(2026, time.March, 8, 0, 0, 0, 0, SeattleTimeZone))
+	require.NotNil(t, s)
+	assert.Equal(t, 2, s.Number)
+	assert.Equal(t, 3, s.Count)
+	assert.Equal(t, "x", s.GroupID)
+}
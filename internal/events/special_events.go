@@ -42,6 +42,11 @@ type specialEventRecord struct {
 	ShortDescription string `dynamodbav:"short_description"`
 	RawDescription   string `dynamodbav:"raw_description"`
 	RawTime          int64  `dynamodbav:"raw_time"`
+	Status           string `dynamodbav:"status"`
+	Category         string `dynamodbav:"category"`
+	TicketsURL       string `dynamodbav:"tickets_url"`
+	InfoURL          string `dynamodbav:"info_url"`
+	ImageURL         string `dynamodbav:"image_url"`
 }
 
 func specialEventsForDate(ctx context.Context, t time.Time) ([]*Event, error) {
@@ -76,8 +81,8 @@ func specialEventsForDate(ctx context.Context, t time.Time) ([]*Event, error) {
 		}
 
 		for _, curr := range pageItems {
-			events = append(events, &Event{
-				ID:               fmt.Sprintf("%s-%s", curr.Date, curr.Slug),
+			event := &Event{
+				ID:               sourceID(SourceSpecial, fmt.Sprintf("%s-%s", curr.Date, curr.Slug)),
 				TeamName:         curr.TeamName,
 				Venue:            curr.Venue,
 				LocalTime:        curr.LocalTime,
@@ -85,7 +90,13 @@ func specialEventsForDate(ctx context.Context, t time.Time) ([]*Event, error) {
 				ShortDescription: curr.ShortDescription,
 				RawDescription:   curr.RawDescription,
 				RawTime:          curr.RawTime,
-			})
+				Status:           EventStatus(curr.Status).Normalized(),
+				Category:         EventCategory(curr.Category).Normalized(),
+				ImageURL:         curr.ImageURL,
+			}
+			event.addLink(LinkKindTickets, curr.TicketsURL)
+			event.addLink(LinkKindInfo, curr.InfoURL)
+			events = append(events, event)
 		}
 	}
 
@@ -22,6 +22,17 @@ const (
 
 	SubTypeIDTouringFacility = "KZFzBErXgnZfZ7vAvv"
 	SegmentTypeSports        = "KZFzniwnSyZfZ7v7nE"
+	SegmentTypeMusic         = "KZFzniwnSyZfZ7v7nJ"
+
+	// TicketmasterMultiDayLookbackDays is how far back we look for multi-day events (festivals, tournaments) that
+	// started before today but are still running
+	TicketmasterMultiDayLookbackDays = 7
+
+	ticketmasterMaxImageWidth = 1200
+
+	// ticketmasterPageSize is big enough that a venue's events for the lookback and the next two days fit on one page,
+	// so we almost never need more than one request per venue
+	ticketmasterPageSize = 100
 )
 
 // seattleVenueMap is a map of venues to ticketmaster's internal venue ID for venues we should look at
@@ -61,6 +72,10 @@ type ticketmasterFetcher struct {
 	limiter       *rate.Limiter
 	apiKey        string
 	baseURL       string
+
+	// multiDayLookbackDays is how many days before today the query starts, to find multi-day events that are still
+	// running. If this is 0, we only find multi-day events that start today or tomorrow
+	multiDayLookbackDays int
 }
 
 func beginningOfDay(t time.Time) time.Time {
@@ -83,11 +98,6 @@ func eventShouldBeIgnored(e *TicketmasterEvent) bool {
 		}
 	}
 
-	if e.Dates.Status.Code == "cancelled" {
-		log.Info().Str("name", e.Name).Str("venue_name", venueName).Msg("event is cancelled")
-		return true
-	}
-
 	if e.Dates.Start.DateTBD || e.Dates.Start.DateTBA {
 		log.Info().Str("name", e.Name).Str("venue_name", venueName).Msg("time is TBA")
 		return true
@@ -133,11 +143,88 @@ func eventShouldBeIgnored(e *TicketmasterEvent) bool {
 	return false
 }
 
-func (tm *ticketmasterFetcher) buildInternalEvent(e TicketmasterEvent, venueName string) (*Event, error) {
+// multiDayEnd figures out the last day of a multi-day event. If ticketmaster doesn't tell us when the event ends, we
+// return false and the event gets treated like any other single day event
+func multiDayEnd(e *TicketmasterEvent) (time.Time, bool) {
+	if !e.Dates.SpanMultipleDays {
+		return time.Time{}, false
+	}
+
+	if e.Dates.End.LocalDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", e.Dates.End.LocalDate, SeattleTimeZone)
+		if err == nil {
+			return end, true
+		}
+		log.Warn().Err(err).Str("event_name", e.Name).Str("end_local_date", e.Dates.End.LocalDate).Msg("could not parse end date of multi-day event")
+	}
+
+	if !e.Dates.End.DateTime.IsZero() {
+		return e.Dates.End.DateTime.In(SeattleTimeZone), true
+	}
+
+	log.Warn().Str("event_name", e.Name).Msg("multi-day event has no end date")
+	return time.Time{}, false
+}
+
+// ticketmasterStatus maps ticketmaster's status codes to ours. Anything we don't know about (onsale, offsale, etc.) is
+// just a normal event as far as we're concerned.
+func ticketmasterStatus(code string) EventStatus {
+	switch code {
+	case "cancelled", "canceled":
+		return StatusCancelled
+	case "postponed":
+		return StatusPostponed
+	case "rescheduled":
+		return StatusRescheduled
+	default:
+		return StatusScheduled
+	}
+}
+
+// ticketmasterImage picks the widest 16:9 image for the event that isn't absurdly large (ticketmaster includes the
+// original source image, which can be huge). Ticketmaster marks generic placeholder images as fallbacks, and those
+// aren't worth showing.
+func ticketmasterImage(e *TicketmasterEvent) string {
+	var best string
+	bestWidth := 0
+	for _, curr := range e.Images {
+		if curr.Fallback || curr.Ratio != "16_9" || curr.Width > ticketmasterMaxImageWidth {
+			continue
+		}
+		if curr.Width > bestWidth {
+			best = curr.Url
+			bestWidth = curr.Width
+		}
+	}
+
+	return best
+}
+
+// ticketmasterCategory maps the segment of the event's primary classification to our category
+func ticketmasterCategory(e *TicketmasterEvent) EventCategory {
+	for _, curr := range e.Classifications {
+		if !curr.Primary {
+			continue
+		}
+		switch curr.Segment.Id {
+		case SegmentTypeSports:
+			return CategorySports
+		case SegmentTypeMusic:
+			return CategoryMusic
+		}
+	}
+	return CategoryOther
+}
+
+func (tm *ticketmasterFetcher) buildInternalEvent(e TicketmasterEvent, venueName string, session *Session) (*Event, error) {
 	var seattleTeam string
+	var teamPage string
 	for _, curr := range e.Embedded.Attractions {
 		if team := tm.attractionIDs[curr.Id]; team != "" {
 			seattleTeam = team
+			if len(curr.ExternalLinks.Homepage) > 0 {
+				teamPage = curr.ExternalLinks.Homepage[0].Url
+			}
 			break
 		}
 	}
@@ -157,14 +244,33 @@ func (tm *ticketmasterFetcher) buildInternalEvent(e TicketmasterEvent, venueName
 		eventTime = eventTime.Add(12 * time.Hour)
 	}
 
+	eventID := sourceID(SourceTicketmaster, e.Id)
+	var dayDescription string
+	if session != nil && session.Kind == SessionKindMultiDay {
+		// assume every day of the event starts at the same time as the first one. Each day also needs its own ID so
+		// things like the calendar don't think day 2 is the same thing as day 1
+		eventTime = eventTime.AddDate(0, 0, session.Number-1)
+		eventID = sourceID(SourceTicketmaster, fmt.Sprintf("%s-%d", e.Id, session.Number))
+		dayDescription = fmt.Sprintf(" (day %d of %d)", session.Number, session.Count)
+	}
+
 	if seattleTeam == "" {
 		// not a seattle sports team, just take event name and build that event
-		return &Event{
-			ID:               e.Id,
-			ShortDescription: fmt.Sprintf("%s is at %s", e.Name, venueName),
-			RawDescription:   fmt.Sprintf("%s is at %s. It starts at %s", e.Name, venueName, eventTimeFormatted),
+		event := &Event{
+			ID:               eventID,
+			Venue:            venueName,
+			Name:             e.Name,
+			LocalTime:        eventTimeFormatted,
+			ShortDescription: fmt.Sprintf("%s is at %s%s", e.Name, venueName, dayDescription),
+			RawDescription:   fmt.Sprintf("%s is at %s%s. It starts at %s", e.Name, venueName, dayDescription, eventTimeFormatted),
 			RawTime:          eventTime.Unix(),
-		}, nil
+			Session:          session,
+			Status:           ticketmasterStatus(e.Dates.Status.Code),
+			Category:         ticketmasterCategory(&e),
+			ImageURL:         ticketmasterImage(&e),
+		}
+		event.addLink(LinkKindTickets, e.Url)
+		return event, nil
 	}
 
 	// this code assumes there are only two "attractions" ... that should be good for any sports match?
@@ -183,20 +289,28 @@ func (tm *ticketmasterFetcher) buildInternalEvent(e TicketmasterEvent, venueName
 		opponentTeam = "some unknown opponent"
 	}
 
-	return &Event{
-		ID:        e.Id,
+	event := &Event{
+		ID:        eventID,
 		TeamName:  seattleTeam,
 		Venue:     venueName,
 		LocalTime: eventTimeFormatted,
 		Opponent:  opponentTeam,
 		RawTime:   eventTime.Unix(),
-	}, nil
+		Session:   session,
+		Status:    ticketmasterStatus(e.Dates.Status.Code),
+		Category:  CategorySports,
+		ImageURL:  ticketmasterImage(&e),
+	}
+	event.addLink(LinkKindTickets, e.Url)
+	event.addLink(LinkKindTeam, teamPage)
+	return event, nil
 }
 
-func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueName string, venueID string, startDate time.Time, endDate time.Time, seattleToday time.Time, seattleTomorrow time.Time) ([]*Event, []*Event, error) {
+// searchEvents gets one page of the venue's events between startDate and endDate, newest first
+func (tm *ticketmasterFetcher) searchEvents(ctx context.Context, venueName string, venueID string, startDate time.Time, endDate time.Time, page int) (*TicketmasterEventSearchResponse, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(TicketmasterEventSearchAPI, tm.baseURL), nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	q := req.URL.Query()
@@ -204,6 +318,11 @@ func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueNam
 	q.Add("apikey", tm.apiKey)
 	q.Add("startDateTime", startDate.Format(time.RFC3339))
 	q.Add("endDateTime", endDate.Format(time.RFC3339))
+	q.Add("size", strconv.Itoa(ticketmasterPageSize))
+	q.Add("page", strconv.Itoa(page))
+	// newest first, so if there's more than a page, what gets pushed off the first one is the lookback, which we
+	// usually don't need
+	q.Add("sort", "date,desc")
 	req.URL.RawQuery = q.Encode()
 
 	log.
@@ -211,11 +330,12 @@ func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueNam
 		Str("venue_id", venueID).
 		Str("start_date_time", startDate.Format(time.RFC3339)).
 		Str("end_date_time", endDate.Format(time.RFC3339)).
+		Int("page", page).
 		Msg("querying ticketmaster api")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -228,10 +348,10 @@ func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueNam
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			log.Error().Err(err).Str("status", resp.Status).Msg("could not read error response body")
-			return nil, nil, fmt.Errorf("events: getEventForVenueID: could not read error body: %w", err)
+			return nil, fmt.Errorf("events: getEventForVenueID: could not read error body: %w", err)
 		}
 		log.Error().Str("status", resp.Status).Msg("error retrieving data from ticketmaster")
-		return nil, nil, fmt.Errorf("events: getEventForVenueID: could not retireve data from ticketmaster: %s", string(body))
+		return nil, fmt.Errorf("events: getEventForVenueID: could not retireve data from ticketmaster: %s", string(body))
 	}
 
 	remainingRequestCount := resp.Header.Get("Rate-Limit-Available")
@@ -252,13 +372,38 @@ func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueNam
 	var payload TicketmasterEventSearchResponse
 	err = json.NewDecoder(resp.Body).Decode(&payload)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueName string, venueID string, startDate time.Time, endDate time.Time, seattleToday time.Time, seattleTomorrow time.Time) ([]*Event, []*Event, error) {
+	var found []TicketmasterEvent
+	for page := 0; ; page++ {
+		if page > 0 {
+			// only happens when a venue has more events in the window than fit on a page
+			err := tm.limiter.Wait(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("could not wait for ticketmaster rate limiter")
+			}
+		}
+
+		payload, err := tm.searchEvents(ctx, venueName, venueID, startDate, endDate, page)
+		if err != nil {
+			return nil, nil, err
+		}
+		found = append(found, payload.Embedded.Events...)
+
+		if page+1 >= payload.Page.TotalPages {
+			break
+		}
 	}
 
 	var today []*Event
 	var tomorrow []*Event
 
-	for _, e := range payload.Embedded.Events {
+	for _, e := range found {
 
 		if eventShouldBeIgnored(&e) {
 			log.Info().Str("venue", venueName).Str("event_name", e.Name).Msg("ignoring event")
@@ -267,7 +412,23 @@ func (tm *ticketmasterFetcher) getEventsForVenueID(ctx context.Context, venueNam
 
 		log.Info().Str("venue_name", venueName).Str("event_name", e.Name).Msg("found event from ticketmaster")
 
-		event, err := tm.buildInternalEvent(e, venueName)
+		if end, ok := multiDayEnd(&e); ok {
+			// multi-day events get an entry on every day they run, so they might show up both today and tomorrow
+			start := e.Dates.Start.DateTime.In(SeattleTimeZone)
+			if s := multiDaySession(e.Id, start, end, seattleToday); s != nil {
+				if event, err := tm.buildInternalEvent(e, venueName, s); err == nil {
+					today = append(today, event)
+				}
+			}
+			if s := multiDaySession(e.Id, start, end, seattleTomorrow); s != nil {
+				if event, err := tm.buildInternalEvent(e, venueName, s); err == nil {
+					tomorrow = append(tomorrow, event)
+				}
+			}
+			continue
+		}
+
+		event, err := tm.buildInternalEvent(e, venueName, nil)
 		if err != nil {
 			continue
 		}
@@ -291,6 +452,10 @@ func (tm *ticketmasterFetcher) GetEvents(ctx context.Context, seattleToday time.
 	start := beginningOfDay(seattleToday)
 	end := start.AddDate(0, 0, 2)
 
+	// events that started before today won't show up unless we start looking earlier. Anything that isn't running
+	// today or tomorrow gets dropped when we sort things in to days
+	queryStart := start.AddDate(0, 0, -tm.multiDayLookbackDays)
+
 	var todayEvents []*Event
 	var tomorrowEvents []*Event
 
@@ -302,7 +467,7 @@ func (tm *ticketmasterFetcher) GetEvents(ctx context.Context, seattleToday time.
 
 		var foundToday []*Event
 		var foundTomorrow []*Event
-		foundToday, foundTomorrow, err = tm.getEventsForVenueID(ctx, venueName, venueID, start, end, seattleToday, seattleTomorrow)
+		foundToday, foundTomorrow, err = tm.getEventsForVenueID(ctx, venueName, venueID, queryStart, end, seattleToday, seattleTomorrow)
 		if err != nil {
 			return nil, nil, fmt.Errorf("events: getTicketmasterEvents: could not query for ticketmaster data: %w", err)
 		}
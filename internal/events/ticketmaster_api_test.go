@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -35,14 +36,21 @@ func TestTicketmasterFetcher_GetEvents(t *testing.T) {
 			checkToday: func(t *testing.T, events []*Event) {
 				assert.Len(t, events, 1)
 				returnedEvent := events[0]
-				assert.Equal(t, "vvG1HZbMO06yRa", returnedEvent.ID)
+				assert.Equal(t, "ticketmaster:vvG1HZbMO06yRa", returnedEvent.ID)
 				assert.Equal(t, "Jo Koy: Just Being Koy Tour is at Climate Pledge Arena. It starts at 8:00 PM", returnedEvent.RawDescription)
 				assert.Equal(t, "Jo Koy: Just Being Koy Tour is at Climate Pledge Arena", returnedEvent.ShortDescription)
+				assert.Equal(t, "Jo Koy: Just Being Koy Tour", returnedEvent.Name)
+				assert.Equal(t, StatusScheduled, returnedEvent.Status)
+				assert.Equal(t, CategoryOther, returnedEvent.Category)
+				require.NotNil(t, returnedEvent.PrimaryLink())
+				assert.Equal(t, LinkKindTickets, returnedEvent.PrimaryLink().Kind)
+				assert.Equal(t, "https://www.ticketmaster.com/jo-koy-just-being-koy-tour-seattle-washington-02-14-2026/event/0F006378241F9BC9", returnedEvent.PrimaryLink().URL)
+				assert.Equal(t, "https://s1.ticketm.net/dam/a/fb8/e45d0f19-ccb4-4ee3-93d3-7c3b1ff8efb8_1486581_RETINA_LANDSCAPE_16_9.jpg", returnedEvent.ImageURL)
 			},
 			checkTomorrow: func(t *testing.T, events []*Event) {
 				assert.Len(t, events, 1)
 				returnedEvent := events[0]
-				assert.Equal(t, "vvG1HZbSbGrpbV", returnedEvent.ID)
+				assert.Equal(t, "ticketmaster:vvG1HZbSbGrpbV", returnedEvent.ID)
 				assert.Equal(t, "GHOST: Skeletour World Tour 2026 is at Climate Pledge Arena. It starts at 8:00 PM", returnedEvent.RawDescription)
 				assert.Equal(t, "GHOST: Skeletour World Tour 2026 is at Climate Pledge Arena", returnedEvent.ShortDescription)
 			},
@@ -54,14 +62,15 @@ func TestTicketmasterFetcher_GetEvents(t *testing.T) {
 			checkToday: func(t *testing.T, events []*Event) {
 				assert.Len(t, events, 1)
 
-				assert.Equal(t, "vvG1HZbURG_tsM", events[0].ID)
+				assert.Equal(t, "ticketmaster:vvG1HZbURG_tsM", events[0].ID)
 				assert.Equal(t, "Battle of the Sound: Seattle Thunderbirds vs Everett Silvertips is at Climate Pledge Arena. It starts at 6:05 PM", events[0].RawDescription)
 				assert.Equal(t, "Battle of the Sound: Seattle Thunderbirds vs Everett Silvertips is at Climate Pledge Arena", events[0].ShortDescription)
+				assert.Equal(t, CategorySports, events[0].Category)
 			},
 			checkTomorrow: func(t *testing.T, events []*Event) {
 				assert.Len(t, events, 1)
 
-				assert.Equal(t, "vvG1HZbRwIT3PJ", events[0].ID)
+				assert.Equal(t, "ticketmaster:vvG1HZbRwIT3PJ", events[0].ID)
 				assert.Equal(t, "The Harlem Globetrotters 100 Year Tour is at Climate Pledge Arena. It starts at 3:00 PM", events[0].RawDescription)
 				assert.Equal(t, "The Harlem Globetrotters 100 Year Tour is at Climate Pledge Arena", events[0].ShortDescription)
 			},
@@ -88,6 +97,28 @@ func TestTicketmasterFetcher_GetEvents(t *testing.T) {
 				assert.Empty(t, events)
 			},
 		},
+		{
+			name: "Multi-day event",
+			file: "multi_day.json",
+			date: time.Date(2026, time.February, 14, 0, 0, 0, 0, SeattleTimeZone),
+			checkToday: func(t *testing.T, events []*Event) {
+				require.Len(t, events, 1)
+				require.NotNil(t, events[0].Session)
+				assert.Equal(t, SessionKindMultiDay, events[0].Session.Kind)
+				assert.Equal(t, 1, events[0].Session.Number)
+				assert.Equal(t, 3, events[0].Session.Count)
+				assert.Equal(t, "vvG1HZbMO06yRa", events[0].Session.GroupID)
+				assert.Equal(t, "ticketmaster:vvG1HZbMO06yRa-1", events[0].ID)
+				assert.Equal(t, "Jo Koy: Just Being Koy Tour is at Climate Pledge Arena (day 1 of 3). It starts at 8:00 PM", events[0].RawDescription)
+			},
+			checkTomorrow: func(t *testing.T, events []*Event) {
+				require.Len(t, events, 1)
+				require.NotNil(t, events[0].Session)
+				assert.Equal(t, 2, events[0].Session.Number)
+				assert.Equal(t, "Jo Koy: Just Being Koy Tour is at Climate Pledge Arena (day 2 of 3). It starts at 8:00 PM", events[0].RawDescription)
+				assert.Equal(t, time.Date(2026, time.February, 15, 20, 0, 0, 0, SeattleTimeZone).Unix(), events[0].RawTime)
+			},
+		},
 		{
 			name: "Duplicated kraken events",
 			file: "duplicated_kraken.json",
@@ -156,3 +187,93 @@ func TestTicketmasterFetcher_GetEvents(t *testing.T) {
 		})
 	}
 }
+
+func readTicketmasterFixture(t *testing.T, file string) TicketmasterEventSearchResponse {
+	t.Helper()
+
+	output, err := testData.ReadFile("testdata/" + file)
+	require.NoError(t, err)
+
+	var response TicketmasterEventSearchResponse
+	require.NoError(t, json.Unmarshal(output, &response))
+	return response
+}
+
+func TestTicketmasterFetcher_Lookback(t *testing.T) {
+	// the multi-day event runs from the 14th to the 16th, so on the 15th it started before today
+	date := time.Date(2026, time.February, 15, 0, 0, 0, 0, SeattleTimeZone)
+
+	tests := map[string]struct {
+		pages            []TicketmasterEventSearchResponse
+		expectedRequests int
+	}{
+		"one page": {
+			pages:            []TicketmasterEventSearchResponse{readTicketmasterFixture(t, "multi_day.json")},
+			expectedRequests: 1,
+		},
+		"truncated": {
+			// the ongoing event got pushed to the second page
+			pages:            []TicketmasterEventSearchResponse{readTicketmasterFixture(t, "ignore_fanfest.json"), readTicketmasterFixture(t, "multi_day.json")},
+			expectedRequests: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				require.NoError(t, r.ParseForm())
+
+				parsedStart, err := time.Parse(time.RFC3339, r.FormValue("startDateTime"))
+				require.NoError(t, err)
+				assert.True(t, parsedStart.Equal(date.AddDate(0, 0, -TicketmasterMultiDayLookbackDays)), "query starts at %s", parsedStart)
+				assert.Equal(t, "date,desc", r.FormValue("sort"))
+
+				page, err := strconv.Atoi(r.FormValue("page"))
+				require.NoError(t, err)
+				require.Less(t, page, len(test.pages))
+
+				response := test.pages[page]
+				response.Page.Number = page
+				response.Page.TotalPages = len(test.pages)
+				require.NoError(t, json.NewEncoder(w).Encode(response))
+			}))
+			defer srv.Close()
+
+			f := &ticketmasterFetcher{
+				venues:        map[string]string{"Climate Pledge Arena": "CPA-VENUE-ID"},
+				attractionIDs: seattleTeamAttractionIDs,
+				limiter:       rate.NewLimiter(10, 1),
+				apiKey:        "test-api-key",
+				baseURL:       srv.URL,
+
+				multiDayLookbackDays: TicketmasterMultiDayLookbackDays,
+			}
+
+			today, tomorrow, err := f.GetEvents(context.TODO(), date, date.AddDate(0, 0, 1))
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRequests, requests)
+
+			require.Len(t, today, 1)
+			assert.Equal(t, "ticketmaster:vvG1HZbMO06yRa-2", today[0].ID)
+			require.Len(t, tomorrow, 1)
+			assert.Equal(t, "ticketmaster:vvG1HZbMO06yRa-3", tomorrow[0].ID)
+		})
+	}
+}
+
+func TestTicketmasterStatus(t *testing.T) {
+	assert.Equal(t, StatusScheduled, ticketmasterStatus("onsale"))
+	assert.Equal(t, StatusScheduled, ticketmasterStatus("offsale"))
+	assert.Equal(t, StatusCancelled, ticketmasterStatus("cancelled"))
+	assert.Equal(t, StatusPostponed, ticketmasterStatus("postponed"))
+	assert.Equal(t, StatusRescheduled, ticketmasterStatus("rescheduled"))
+
+	postponed := &Event{Status: StatusPostponed}
+	rescheduled := &Event{Status: StatusRescheduled}
+	assert.False(t, AnyHappening([]*Event{postponed}))
+	assert.True(t, AnyHappening([]*Event{postponed, rescheduled}))
+	assert.Equal(t, "POSTPONED", postponed.StatusLabel())
+	assert.Empty(t, (&Event{}).StatusLabel())
+}
@@ -6,6 +6,12 @@ type TicketmasterEventSearchResponse struct {
 	Embedded struct {
 		Events []TicketmasterEvent `json:"events"`
 	} `json:"_embedded"`
+	Page struct {
+		Size          int `json:"size"`
+		TotalElements int `json:"totalElements"`
+		TotalPages    int `json:"totalPages"`
+		Number        int `json:"number"`
+	} `json:"page"`
 }
 
 type TicketmasterEvent struct {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -28,9 +29,15 @@ const (
 
 type espnTeamResponse struct {
 	Team struct {
-		ID        string `json:"id"`
-		UID       string `json:"uid"`
+		ID    string     `json:"id"`
+		UID   string     `json:"uid"`
+		Links []espnLink `json:"links"`
+		Logos []struct {
+			Href string   `json:"href"`
+			Rel  []string `json:"rel"`
+		} `json:"logos"`
 		NextEvent []struct {
+			Links        []espnLink `json:"links"`
 			Competitions []struct {
 				Id         string `json:"id"`
 				Date       string `json:"date"`
@@ -88,8 +95,52 @@ type espnTeamResponse struct {
 	} `json:"team"`
 }
 
+type espnLink struct {
+	Rel  []string `json:"rel"`
+	Href string   `json:"href"`
+}
+
+// espnLinkWithRel finds the first link tagged with the given rel (ESPN tags links with things like "clubhouse",
+// "summary", "tickets", etc.)
+func espnLinkWithRel(links []espnLink, rel string) string {
+	for _, curr := range links {
+		if slices.Contains(curr.Rel, rel) {
+			return curr.Href
+		}
+	}
+	return ""
+}
+
+// espnStatus maps ESPN's status type names to ours. Games that are delayed or in progress are still happening, so
+// they count as scheduled.
+func espnStatus(name string) EventStatus {
+	switch name {
+	case "STATUS_POSTPONED":
+		return StatusPostponed
+	case "STATUS_CANCELED", "STATUS_CANCELLED":
+		return StatusCancelled
+	default:
+		return StatusScheduled
+	}
+}
+
+// espnHomeAway figures out which side we're on. Neutral site games (bowl games, tournaments at Climate Pledge, etc.)
+// still list one team as home, but neither team really is.
+func espnHomeAway(neutralSite bool, homeAway string) HomeAway {
+	switch {
+	case neutralSite:
+		return HomeAwayNeutral
+	case homeAway == "home":
+		return HomeAwayHome
+	case homeAway == "away":
+		return HomeAwayAway
+	default:
+		return ""
+	}
+}
+
 // queryESPNAndAdd uses an undocumented ESPN API. This is liable to break at any moment :(
-func queryESPNAndAdd(ctx context.Context, url string, teamName string, venue string, today *[]*Event, tomorrow *[]*Event, seattleToday time.Time, seattleTomorrow time.Time) error {
+func queryESPNAndAdd(ctx context.Context, url string, teamName string, today *[]*Event, tomorrow *[]*Event, seattleToday time.Time, seattleTomorrow time.Time) error {
 	log.Info().Str(seattleTeamKey, teamName).Msg("querying espn for team info")
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -132,6 +183,11 @@ func queryESPNAndAdd(ctx context.Context, url string, teamName string, venue str
 		return fmt.Errorf("events: queryESPNTeam: empty response payload")
 	}
 
+	var teamLogo string
+	if len(payload.Team.Logos) > 0 {
+		teamLogo = payload.Team.Logos[0].Href
+	}
+
 	for _, curr := range payload.Team.NextEvent {
 		if len(curr.Competitions) <= 0 {
 			log.Warn().Str(seattleTeamKey, teamName).Msg("no games found")
@@ -149,12 +205,23 @@ func queryESPNAndAdd(ctx context.Context, url string, teamName string, venue str
 			// Keep going, assume the first two are the home and away teams
 		}
 
-		homeTeam := competition.Competitors[0]
-		awayTeam := competition.Competitors[1]
-		if homeTeam.HomeAway != "home" {
-			homeTeam = competition.Competitors[1]
-			awayTeam = competition.Competitors[0]
+		// figure out which competitor is us by ID. We can't trust home/away for this since Washington can be listed as
+		// the away team at a neutral site (or even at a Seattle venue)
+		usIdx, themIdx := -1, -1
+		for i, c := range competition.Competitors[:2] {
+			if c.Id == payload.Team.ID || c.Team.Id == payload.Team.ID {
+				usIdx, themIdx = i, 1-i
+			}
+		}
+		if usIdx < 0 {
+			log.Warn().Str(seattleTeamKey, teamName).Str("competition_id", competition.Id).Msg("could not find our team in competitors, assuming we are the home team")
+			usIdx, themIdx = 0, 1
+			if competition.Competitors[0].HomeAway != "home" {
+				usIdx, themIdx = 1, 0
+			}
 		}
+		us := competition.Competitors[usIdx]
+		opponent := competition.Competitors[themIdx]
 
 		gameTime, err := time.Parse("2006-01-02T15:04Z", competition.Date)
 		if err != nil {
@@ -164,28 +231,44 @@ func queryESPNAndAdd(ctx context.Context, url string, teamName string, venue str
 
 		seattleStart := gameTime.In(SeattleTimeZone)
 
-		if competition.Venue.FullName == venue {
-			if isDay(seattleToday, seattleStart) {
-				log.Info().Str(seattleTeamKey, teamName).Str("opponent", awayTeam.Team.DisplayName).Msg("found game for today")
-				*today = append(*today, &Event{
-					ID:        competition.Id,
-					TeamName:  teamName,
-					Venue:     competition.Venue.FullName,
-					LocalTime: gameTime.In(SeattleTimeZone).Format(localTimeDateFormat),
-					Opponent:  awayTeam.Team.DisplayName,
-					RawTime:   gameTime.Unix(),
-				})
-			} else if isDay(seattleTomorrow, seattleStart) {
-				log.Info().Str(seattleTeamKey, teamName).Str("opponent", awayTeam.Team.DisplayName).Msg("found game for tomorrow")
-				*tomorrow = append(*tomorrow, &Event{
-					ID:        competition.Id,
-					TeamName:  teamName,
-					Venue:     competition.Venue.FullName,
-					LocalTime: gameTime.In(SeattleTimeZone).Format(localTimeDateFormat),
-					Opponent:  awayTeam.Team.DisplayName,
-					RawTime:   gameTime.Unix(),
-				})
-			}
+		// every game at one of our venues is listed, even if Washington is the away team (like a game against
+		// Seattle U at Climate Pledge). Games away from Seattle never are.
+		gameVenue := LookupVenue(competition.Venue.FullName)
+		if gameVenue == nil {
+			continue
+		}
+
+		var links []Link
+		if href := espnLinkWithRel(curr.Links, "tickets"); href != "" {
+			links = append(links, Link{Kind: LinkKindTickets, URL: href})
+		}
+		if href := espnLinkWithRel(curr.Links, "summary"); href != "" {
+			links = append(links, Link{Kind: LinkKindInfo, URL: href})
+		}
+		if href := espnLinkWithRel(payload.Team.Links, "clubhouse"); href != "" {
+			links = append(links, Link{Kind: LinkKindTeam, URL: href})
+		}
+
+		event := &Event{
+			ID:        sourceID(SourceESPN, competition.Id),
+			TeamName:  teamName,
+			Venue:     gameVenue.Name,
+			LocalTime: seattleStart.Format(localTimeDateFormat),
+			Opponent:  opponent.Team.DisplayName,
+			RawTime:   gameTime.Unix(),
+			Status:    espnStatus(competition.Status.Type.Name),
+			Category:  CategorySports,
+			HomeAway:  espnHomeAway(competition.NeutralSite, us.HomeAway),
+			Links:     links,
+			ImageURL:  teamLogo,
+		}
+
+		if isDay(seattleToday, seattleStart) {
+			log.Info().Str(seattleTeamKey, teamName).Str("opponent", event.Opponent).Str("home_away", string(event.HomeAway)).Msg("found game for today")
+			*today = append(*today, event)
+		} else if isDay(seattleTomorrow, seattleStart) {
+			log.Info().Str(seattleTeamKey, teamName).Str("opponent", event.Opponent).Str("home_away", string(event.HomeAway)).Msg("found game for tomorrow")
+			*tomorrow = append(*tomorrow, event)
 		}
 	}
 
@@ -196,17 +279,17 @@ func GetUWGames(ctx context.Context, seattleToday time.Time, seattleTomorrow tim
 	var today []*Event
 	var tomorrow []*Event
 
-	err := queryESPNAndAdd(ctx, huskiesFootballURL, huskiesFootballName, huskyStadium, &today, &tomorrow, seattleToday, seattleTomorrow)
+	err := queryESPNAndAdd(ctx, huskiesFootballURL, huskiesFootballName, &today, &tomorrow, seattleToday, seattleTomorrow)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = queryESPNAndAdd(ctx, huskiesMensBasketballURL, huskiesMensBasketballName, alaskaAirlinesArena, &today, &tomorrow, seattleToday, seattleTomorrow)
+	err = queryESPNAndAdd(ctx, huskiesMensBasketballURL, huskiesMensBasketballName, &today, &tomorrow, seattleToday, seattleTomorrow)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	err = queryESPNAndAdd(ctx, huskiesWomensBasketballURL, huskiesWomensBasketballName, alaskaAirlinesArena, &today, &tomorrow, seattleToday, seattleTomorrow)
+	err = queryESPNAndAdd(ctx, huskiesWomensBasketballURL, huskiesWomensBasketballName, &today, &tomorrow, seattleToday, seattleTomorrow)
 	if err != nil {
 		return nil, nil, err
 	}
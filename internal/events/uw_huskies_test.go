@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func espnCompetitor(id string, homeAway string, name string) string {
+	return fmt.Sprintf(`{"id":%q,"homeAway":%q,"team":{"id":%q,"displayName":%q}}`, id, homeAway, id, name)
+}
+
+func espnNextEvent(id string, date string, venue string, neutralSite bool, competitors ...string) string {
+	return fmt.Sprintf(`{"competitions":[{"id":%q,"date":%q,"neutralSite":%t,"venue":{"fullName":%q},"competitors":[%s,%s],"status":{"type":{"name":"STATUS_SCHEDULED"}}}]}`,
+		id, date, neutralSite, venue, competitors[0], competitors[1])
+}
+
+func TestQueryESPNAndAdd(t *testing.T) {
+	payload := fmt.Sprintf(`{"team":{"id":"264","uid":"s:40~l:41~t:264","nextEvent":[%s,%s,%s,%s]}}`,
+		// home game, but ESPN uses the long name of the venue
+		espnNextEvent("home", "2026-03-07T03:00Z", "Alaska Airlines Arena at Hec Edmundson Pavilion", false,
+			espnCompetitor("264", "home", "Washington Huskies"), espnCompetitor("2", "away", "Oregon Ducks")),
+		// neutral site tournament game in Seattle where we are listed as the away team
+		espnNextEvent("neutral", "2026-03-07T20:00Z", "Climate Pledge Arena", true,
+			espnCompetitor("3", "home", "Gonzaga Bulldogs"), espnCompetitor("264", "away", "Washington Huskies")),
+		// away game at a Seattle venue that isn't neutral site. It's someone else's home game, but it's still in Seattle
+		espnNextEvent("seattle-u", "2026-03-08T03:00Z", "Climate Pledge Arena", false,
+			espnCompetitor("2547", "home", "Seattle U Redhawks"), espnCompetitor("264", "away", "Washington Huskies")),
+		// road game
+		espnNextEvent("away", "2026-03-08T03:00Z", "Matthew Knight Arena", false,
+			espnCompetitor("2", "home", "Oregon Ducks"), espnCompetitor("264", "away", "Washington Huskies")),
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(payload))
+	}))
+	defer srv.Close()
+
+	var today []*Event
+	var tomorrow []*Event
+
+	seattleToday := time.Date(2026, time.March, 6, 0, 0, 0, 0, SeattleTimeZone)
+	err := queryESPNAndAdd(context.TODO(), srv.URL, huskiesMensBasketballName, &today, &tomorrow, seattleToday, seattleToday.AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	require.Len(t, today, 1)
+	assert.Equal(t, "espn:home", today[0].ID)
+	assert.Equal(t, alaskaAirlinesArena, today[0].Venue)
+	assert.Equal(t, "Oregon Ducks", today[0].Opponent)
+	assert.Equal(t, HomeAwayHome, today[0].HomeAway)
+
+	require.Len(t, tomorrow, 2)
+	assert.Equal(t, "espn:neutral", tomorrow[0].ID)
+	assert.Equal(t, "Climate Pledge Arena", tomorrow[0].Venue)
+	assert.Equal(t, "Gonzaga Bulldogs", tomorrow[0].Opponent)
+	assert.Equal(t, huskiesMensBasketballName, tomorrow[0].TeamName)
+	assert.Equal(t, HomeAwayNeutral, tomorrow[0].HomeAway)
+
+	assert.Equal(t, "espn:seattle-u", tomorrow[1].ID)
+	assert.Equal(t, "Seattle U Redhawks", tomorrow[1].Opponent)
+	assert.Equal(t, huskiesMensBasketballName, tomorrow[1].TeamName)
+	assert.Equal(t, HomeAwayAway, tomorrow[1].HomeAway)
+}
+
+func TestLookupVenue(t *testing.T) {
+	assert.Equal(t, "WAMU Theater", LookupVenue("WaMu Theater").Name)
+	assert.Equal(t, "T-Mobile Park", LookupVenue("Safeco Field").Name)
+	assert.Equal(t, "Lumen Field", LookupVenue("  lumen field ").Name)
+	assert.Nil(t, LookupVenue("Moda Center"))
+}
+
+func TestTeamsCatalog(t *testing.T) {
+	names := map[string]bool{}
+	for _, curr := range Teams {
+		names[curr.Name] = true
+	}
+
+	// every team we pull from ticketmaster needs a page
+	for _, name := range seattleTeamAttractionIDs {
+		assert.True(t, names[name], name)
+	}
+
+	kraken := &Event{TeamName: "Seattle Kraken"}
+	assert.True(t, kraken.IsHomeGameFor(Teams[2]))
+	assert.False(t, kraken.IsHomeGameFor(Teams[0]))
+}
+
+func TestIsHomeGameFor_Huskies(t *testing.T) {
+	var huskies *Team
+	for _, curr := range Teams {
+		if curr.Name == huskiesMensBasketballName {
+			huskies = curr
+		}
+	}
+	require.NotNil(t, huskies)
+
+	game := &Event{TeamName: huskiesMensBasketballName, Opponent: "Gonzaga Bulldogs", Venue: "Climate Pledge Arena"}
+	for homeAway, want := range map[HomeAway]bool{
+		HomeAwayHome:    true,
+		HomeAwayAway:    false,
+		HomeAwayNeutral: false,
+		// ESPN reports away games too, so a game it doesn't put us at home for isn't one
+		"": false,
+	} {
+		game.HomeAway = homeAway
+		assert.Equal(t, want, game.IsHomeGameFor(huskies), homeAway)
+	}
+}
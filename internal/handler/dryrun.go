@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lthummus/seattle-sports-today/internal/calendar"
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/uploader"
+)
+
+// dryRun runs the whole publishing pipeline against the real targets, but only reads from them. It prints what would
+// have been published, invalidated and synced to the Google calendar.
+func dryRun(ctx context.Context, event CustomEvent, publishDir string, eventResults *events.EventResults, seattleToday time.Time) error {
+	// a publish directory is only opened for reading, so a dry run doesn't even create it
+	var target uploader.Publisher = uploader.OpenDir(publishDir)
+	if publishDir == "" {
+		var err error
+		target, err = targetPublisher(ctx, publishDir, true)
+		if err != nil {
+			return fmt.Errorf("dryRun: could not set up publishing: %w", err)
+		}
+	}
+
+	// compression goes on the outside so the variants it would publish are recorded too
+	plan := uploader.NewDryRun(target)
+	publisher := withCompression(plan)
+
+	log.Info().Msg("rendering page")
+	artifacts, _, err := renderArtifacts(ctx, eventResults, seattleToday, publisher)
+	if err != nil {
+		return err
+	}
+
+	runID := uploader.NewRunID(time.Now())
+	log.Info().Str("run_id", runID).Int("artifact_count", len(artifacts)).Msg("dry run, checking what would be published")
+	err = uploader.Publish(ctx, publisher, runID, artifacts, event.InvalidateAll, uploader.DefaultKeepReleases)
+	if err != nil {
+		return fmt.Errorf("dryRun: %w", err)
+	}
+
+	// the calendar is only synced when publishing to S3
+	var planned []*calendar.PlannedSync
+	var calendarErr error
+	if publishDir == "" {
+		planned, calendarErr = planGoogleCalendar(ctx, eventResults.TodayEvent)
+		if calendarErr != nil {
+			log.Warn().Err(calendarErr).Msg("could not check google calendar")
+		}
+	}
+
+	fmt.Println(dryRunReport(runID, len(artifacts), plan.Changes(), plan.Invalidations(), publishDir == "", planned, calendarErr))
+	return nil
+}
+
+// dryRunReport describes everything a dry run would have done. Changes under the release prefix are only counted, since
+// they're the same files as the site's.
+func dryRunReport(runID string, artifactCount int, changes []uploader.Change, invalidations []string, syncsCalendar bool, planned []*calendar.PlannedSync, calendarErr error) string {
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "Dry run for release %s. Nothing was changed.\n", runID)
+
+	var site []uploader.Change
+	var staged, deleted, republished int
+	var diffs []string
+	for _, curr := range changes {
+		switch {
+		case strings.HasPrefix(curr.Key, uploader.ReleasesPrefix):
+			if curr.Action == uploader.ActionDelete {
+				deleted++
+			} else if curr.Key != uploader.ReleaseIndexKey {
+				staged++
+			}
+		case curr.SameContents:
+			republished++
+		default:
+			site = append(site, curr)
+			if curr.Diff != "" {
+				diffs = append(diffs, curr.Diff)
+			}
+		}
+	}
+
+	fmt.Fprintf(&sb, "\nSite (%d of %d artifacts would change):\n", len(site), artifactCount)
+	for _, curr := range site {
+		switch curr.Action {
+		case uploader.ActionCreate:
+			fmt.Fprintf(&sb, "  create %s (%d bytes)\n", curr.Key, curr.Size)
+		case uploader.ActionUpdate:
+			fmt.Fprintf(&sb, "  update %s (%d -> %d bytes)\n", curr.Key, curr.LiveSize, curr.Size)
+		default:
+			fmt.Fprintf(&sb, "  %s %s\n", curr.Action, curr.Key)
+		}
+	}
+
+	if republished > 0 {
+		fmt.Fprintf(&sb, "  and %d more would be published again with the same contents\n", republished)
+	}
+
+	fmt.Fprintf(&sb, "\nRelease: stage %d files under %s%s/, update %s, delete %d files from old releases\n", staged, uploader.ReleasesPrefix, runID, uploader.ReleaseIndexKey, deleted)
+
+	fmt.Fprintf(&sb, "\nInvalidations (%d):\n", len(invalidations))
+	for _, curr := range invalidations {
+		fmt.Fprintf(&sb, "  %s\n", curr)
+	}
+
+	sb.WriteString("\nGoogle calendar:\n")
+	switch {
+	case !syncsCalendar:
+		sb.WriteString("  not synced when publishing to a directory\n")
+	case calendarErr != nil:
+		fmt.Fprintf(&sb, "  could not check: %s\n", calendarErr.Error())
+	case len(planned) == 0:
+		sb.WriteString("  no events today\n")
+	}
+	for _, curr := range planned {
+		fmt.Fprintf(&sb, "  %-9s %s", curr.Action, curr.Event.CalendarSummary())
+		if curr.StatusChange != nil {
+			fmt.Fprintf(&sb, " (%s, was %s)", curr.Event.Status.Normalized(), curr.StatusChange.PreviousStatus)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(diffs) > 0 {
+		sb.WriteString("\nDiffs:\n")
+		for _, curr := range diffs {
+			sb.WriteString(curr)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/calendar"
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/uploader"
+)
+
+func TestDryRun_PublishDir(t *testing.T) {
+	ctx := context.Background()
+	seattleToday := time.Date(2026, time.May, 2, 3, 14, 0, 0, events.SeattleTimeZone)
+	publishDir := t.TempDir()
+
+	publisher, err := uploader.NewDir(publishDir)
+	require.NoError(t, err)
+	artifacts, _, err := renderArtifacts(ctx, &events.EventResults{}, seattleToday, publisher)
+	require.NoError(t, err)
+	require.NoError(t, uploader.Publish(ctx, publisher, "run-1", artifacts, false, uploader.DefaultKeepReleases))
+
+	before, err := os.ReadFile(filepath.Join(publishDir, "team", "mariners", "index.html"))
+	require.NoError(t, err)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				TeamName:  "Seattle Mariners",
+				Opponent:  "Houston Astros",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				RawTime:   time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone).Unix(),
+			},
+		},
+	}
+	require.NoError(t, dryRun(ctx, CustomEvent{}, publishDir, results, seattleToday))
+
+	after, err := os.ReadFile(filepath.Join(publishDir, "team", "mariners", "index.html"))
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	assert.NotContains(t, string(after), "Houston Astros")
+
+	index, err := uploader.Releases(ctx, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", index.Current)
+}
+
+func TestDryRun_MissingPublishDir(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 3, 14, 0, 0, events.SeattleTimeZone)
+	publishDir := filepath.Join(t.TempDir(), "site")
+
+	require.NoError(t, dryRun(context.Background(), CustomEvent{}, publishDir, &events.EventResults{}, seattleToday))
+	assert.NoDirExists(t, publishDir)
+}
+
+func TestDryRunReport(t *testing.T) {
+	changes := []uploader.Change{
+		{Key: "index.html", Action: uploader.ActionUpdate, Size: 12, LiveSize: 10, Diff: "--- live/index.html\n+++ rendered/index.html\n"},
+		{Key: "releases/20260502T101400Z/index.html", Action: uploader.ActionCreate, Size: 12},
+		{Key: "releases/20260502T101400Z/manifest.json", Action: uploader.ActionCreate, Size: 100},
+		{Key: "releases/20260418T101400Z/index.html", Action: uploader.ActionDelete},
+		{Key: uploader.ReleaseIndexKey, Action: uploader.ActionUpdate, Size: 200, LiveSize: 180},
+		{Key: "team/mariners/index.html", Action: uploader.ActionCreate, Size: 30},
+		{Key: "robots.txt", Action: uploader.ActionUpdate, Size: 130, LiveSize: 130, SameContents: true},
+	}
+
+	cancelled := &events.Event{TeamName: "Seattle Mariners", Opponent: "Houston Astros", Status: events.StatusCancelled}
+	planned := []*calendar.PlannedSync{
+		{Event: &events.Event{TeamName: "Seattle Kraken", Opponent: "Vancouver Canucks"}, Action: calendar.SyncInsert},
+		{Event: cancelled, Action: calendar.SyncDelete, StatusChange: &calendar.StatusChange{Event: cancelled, PreviousStatus: events.StatusScheduled}},
+	}
+
+	report := dryRunReport("20260502T101400Z", 40, changes, []string{"/index.html", "/team/mariners/index.html"}, true, planned, nil)
+
+	assert.Contains(t, report, "Site (2 of 40 artifacts would change)")
+	assert.Contains(t, report, "  update index.html (10 -> 12 bytes)\n")
+	assert.Contains(t, report, "  create team/mariners/index.html (30 bytes)\n")
+	assert.Contains(t, report, "and 1 more would be published again with the same contents")
+	assert.Contains(t, report, "stage 2 files under releases/20260502T101400Z/, update releases/index.json, delete 1 files from old releases")
+	assert.Contains(t, report, "Invalidations (2):\n  /index.html\n  /team/mariners/index.html\n")
+	assert.Contains(t, report, "  insert    ")
+	assert.Contains(t, report, "(cancelled, was scheduled)")
+	assert.Contains(t, report, "--- live/index.html\n")
+	assert.NotContains(t, report, "releases/20260502T101400Z/index.html")
+
+	report = dryRunReport("20260502T101400Z", 40, nil, nil, false, nil, nil)
+	assert.Contains(t, report, "not synced when publishing to a directory")
+
+	report = dryRunReport("20260502T101400Z", 40, nil, nil, true, nil, errors.New("GOOGLE_CALENDAR_ID not set"))
+	assert.Contains(t, report, "could not check: GOOGLE_CALENDAR_ID not set")
+}
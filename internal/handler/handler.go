@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -13,7 +14,6 @@ import (
 	"github.com/lthummus/seattle-sports-today/internal/events"
 	"github.com/lthummus/seattle-sports-today/internal/notifier"
 	"github.com/lthummus/seattle-sports-today/internal/renderhtml"
-	"github.com/lthummus/seattle-sports-today/internal/renderjson"
 	"github.com/lthummus/seattle-sports-today/internal/secrets"
 	"github.com/lthummus/seattle-sports-today/internal/uploader"
 )
@@ -22,34 +22,183 @@ type CustomEvent struct {
 	Today         string `json:"today"`
 	Upload        bool   `json:"upload"`
 	InvalidateAll bool   `json:"invalidate_all"`
+
+	// Format is what gets printed when running locally. If it is empty, both the JSON and the HTML are printed.
+	Format string `json:"format"`
+
+	// PublishDir publishes the site to a local directory instead of S3. It can also be set with PUBLISH_DIR.
+	PublishDir string `json:"publish_dir"`
+
+	// DryRun checks what would be published to S3 (or PublishDir) and synced to the Google calendar, and prints it
+	// without changing anything
+	DryRun bool `json:"dry_run"`
 }
 
-func insertToGoogleCalendar(ctx context.Context, events []*events.Event) error {
-	// this is a low priority thing...if it doesn't work, we should error, but not blow up
+// pageTheme is the theme every page is rendered with. It is nil (meaning the default theme) unless Init loaded one.
+var pageTheme *renderhtml.Theme
+
+// Init loads the page theme from the directory in THEME_DIR, if it is set. A broken theme is an error here so we find
+// out at startup instead of when we go to publish.
+func Init() error {
+	themeDir := os.Getenv(renderhtml.EnvVarThemeDir)
+	if themeDir == "" {
+		return nil
+	}
+
+	theme, err := renderhtml.LoadTheme(themeDir)
+	if err != nil {
+		return fmt.Errorf("handler: Init: could not load theme: %w", err)
+	}
+
+	log.Info().Str("theme_dir", themeDir).Msg("loaded page theme")
+	pageTheme = theme
+	return nil
+}
+
+// targetPublisher picks where the site gets published. A publish directory always wins, so local runs can preview the
+// whole site without touching S3. Otherwise, we only publish to S3 if we're supposed to be uploading. A nil publisher
+// means nothing gets published.
+func targetPublisher(ctx context.Context, publishDir string, shouldUpload bool) (uploader.Publisher, error) {
+	if publishDir != "" {
+		return uploader.NewDir(publishDir)
+	} else if shouldUpload {
+		return uploader.NewS3(ctx, os.Getenv(uploader.EnvVarBucketName), os.Getenv(uploader.EnvVarDistributionID))
+	}
+	return nil, nil
+}
+
+// withCompression publishes compressed variants too if PUBLISH_COMPRESSED is true
+func withCompression(publisher uploader.Publisher) uploader.Publisher {
+	if os.Getenv(uploader.EnvVarPublishCompressed) == "true" {
+		log.Info().Msg("publishing compressed variants")
+		return uploader.NewCompressed(publisher)
+	}
+	return publisher
+}
+
+// newPublisher is the publisher for the target picked by targetPublisher, with compression if it's turned on
+func newPublisher(ctx context.Context, publishDir string, shouldUpload bool) (uploader.Publisher, error) {
+	publisher, err := targetPublisher(ctx, publishDir, shouldUpload)
+	if err != nil || publisher == nil {
+		return nil, err
+	}
+	return withCompression(publisher), nil
+}
+
+// Rollback makes a previous run's release live again. Like a normal run, it goes to the publish directory if there is
+// one and S3 otherwise.
+func Rollback(ctx context.Context, runID string, publishDir string) error {
+	if publishDir == "" {
+		publishDir = os.Getenv(uploader.EnvVarPublishDir)
+	}
+
+	publisher, err := newPublisher(ctx, publishDir, true)
+	if err != nil {
+		return err
+	}
+
+	err = uploader.Rollback(ctx, publisher, runID)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("run_id", runID).Msg("rolled back")
+	if publishDir == "" {
+		_ = notifier.Notify(ctx, fmt.Sprintf("Rolled back to run %s", runID), notifier.PriorityHigh, notifier.EmojiWarning)
+	}
+	return nil
+}
+
+// Releases returns the releases that can be rolled back to
+func Releases(ctx context.Context, publishDir string) (*uploader.ReleaseIndex, error) {
+	if publishDir == "" {
+		publishDir = os.Getenv(uploader.EnvVarPublishDir)
+	}
 
+	publisher, err := newPublisher(ctx, publishDir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return uploader.Releases(ctx, publisher)
+}
+
+func newCalendarClient(ctx context.Context) (*calendar.Google, error) {
 	calendarID := os.Getenv("GOOGLE_CALENDAR_ID")
 	if calendarID == "" {
-		return fmt.Errorf("insertToGoogleCalendar: GOOGLE_CALENDAR_ID not set")
+		return nil, fmt.Errorf("GOOGLE_CALENDAR_ID not set")
 	}
 
 	credentials, err := secrets.GetSecretString(ctx, os.Getenv("GOOGLE_CREDENTIALS_SECRET_NAME"))
 	if err != nil {
-		return fmt.Errorf("insertToGoogleCalendar: could not get credentials: %w", err)
+		return nil, fmt.Errorf("could not get credentials: %w", err)
 	}
 
 	calendarClient, err := calendar.NewGoogleCalendar(ctx, credentials, calendarID)
 	if err != nil {
-		return fmt.Errorf("insertToGoogleCalendar: could not create Google API client: %w", err)
+		return nil, fmt.Errorf("could not create Google API client: %w", err)
+	}
+	return calendarClient, nil
+}
+
+func insertToGoogleCalendar(ctx context.Context, events []*events.Event) ([]*calendar.StatusChange, error) {
+	// this is a low priority thing...if it doesn't work, we should error, but not blow up
+
+	calendarClient, err := newCalendarClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("insertToGoogleCalendar: %w", err)
 	}
 
+	var changes []*calendar.StatusChange
 	for _, curr := range events {
-		err = calendarClient.CreateEvent(ctx, curr)
+		change, err := calendarClient.SyncEvent(ctx, curr)
 		if err != nil {
-			return err
+			return changes, err
+		}
+		if change != nil {
+			changes = append(changes, change)
 		}
 	}
 
-	return nil
+	return changes, nil
+}
+
+// planGoogleCalendar returns what insertToGoogleCalendar would do, without changing anything
+func planGoogleCalendar(ctx context.Context, events []*events.Event) ([]*calendar.PlannedSync, error) {
+	calendarClient, err := newCalendarClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("planGoogleCalendar: %w", err)
+	}
+
+	planned := make([]*calendar.PlannedSync, 0, len(events))
+	for _, curr := range events {
+		p, err := calendarClient.PlanSync(ctx, curr)
+		if err != nil {
+			return planned, err
+		}
+		planned = append(planned, p)
+	}
+
+	return planned, nil
+}
+
+func statusChangeMessage(changes []*calendar.StatusChange) string {
+	lines := make([]string, len(changes))
+	for i, curr := range changes {
+		lines[i] = fmt.Sprintf("%s is now %s (was %s)", curr.Event.CalendarSummary(), curr.Event.Status.Normalized(), curr.PreviousStatus)
+	}
+
+	return fmt.Sprintf("Event status changed:\n%s", strings.Join(lines, "\n"))
+}
+
+// skippedMessage tells someone which artifacts are stuck until the live copy gets fixed
+func skippedMessage(skipped []skippedArtifact) string {
+	lines := make([]string, len(skipped))
+	for i, curr := range skipped {
+		lines[i] = fmt.Sprintf("%s: %s", curr.Key, curr.Err.Error())
+	}
+
+	return fmt.Sprintf("Could not read what is live, so these won't update until it is repaired or deleted:\n%s", strings.Join(lines, "\n"))
 }
 
 func EventHandler(ctx context.Context, event CustomEvent) error {
@@ -59,6 +208,10 @@ func EventHandler(ctx context.Context, event CustomEvent) error {
 		}
 	}()
 
+	if event.Format != "" && !slices.Contains(OutputFormats, event.Format) {
+		return fmt.Errorf("invalid output format: %s", event.Format)
+	}
+
 	var triggeredByEventBridge bool
 	var seattleToday time.Time
 	var seattleTomorrow time.Time
@@ -83,8 +236,10 @@ func EventHandler(ctx context.Context, event CustomEvent) error {
 	eventResults, err := events.GetTodayAndTomorrowGames(ctx, seattleToday, seattleTomorrow)
 	if err != nil {
 		// if we have an error, that means at least one source failed. `eventResults` will always be non-nil, so might as well work with what we have and
-		// send an alert to my phone
-		_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: could not get today's games: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
+		// send an alert to my phone (unless this is a dry run, which shouldn't bother anybody)
+		if !event.DryRun {
+			_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: could not get today's games: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
+		}
 	}
 
 	log.Info().Int("today_games_found", len(eventResults.TodayEvent)).Int("tomorrow_games_found", len(eventResults.TomorrowEvents)).Msg("found games")
@@ -97,57 +252,83 @@ func EventHandler(ctx context.Context, event CustomEvent) error {
 	})
 
 	for _, curr := range eventResults.TodayEvent {
-		log.Info().Str("team_name", curr.TeamName).Str("venue", curr.Venue).Str("local_time", curr.LocalTime).Str("opponent", curr.Opponent).Int64("raw_time", curr.RawTime).Msg("found event today")
+		log.Info().Str("team_name", curr.TeamName).Str("venue", curr.Venue).Str("local_time", curr.LocalTime).Str("opponent", curr.Opponent).Int64("raw_time", curr.RawTime).Str("status", string(curr.Status)).Msg("found event today")
 	}
 	for _, curr := range eventResults.TomorrowEvents {
-		log.Info().Str("team_name", curr.TeamName).Str("venue", curr.Venue).Str("local_time", curr.LocalTime).Str("opponent", curr.Opponent).Int64("raw_time", curr.RawTime).Msg("found event tomorrow")
+		log.Info().Str("team_name", curr.TeamName).Str("venue", curr.Venue).Str("local_time", curr.LocalTime).Str("opponent", curr.Opponent).Int64("raw_time", curr.RawTime).Str("status", string(curr.Status)).Msg("found event tomorrow")
 	}
 
-	log.Info().Msg("rendering page")
-	page, err := renderhtml.RenderPage(eventResults, seattleToday)
+	runningInDefaultMode := os.Getenv("_HANDLER") != "" && triggeredByEventBridge
+	shouldUploadAnyway := event.Upload || (!triggeredByEventBridge && event.Upload)
+	shouldUpload := runningInDefaultMode || shouldUploadAnyway
+
+	publishDir := event.PublishDir
+	if publishDir == "" {
+		publishDir = os.Getenv(uploader.EnvVarPublishDir)
+	}
+
+	if event.DryRun {
+		return dryRun(ctx, event, publishDir, eventResults, seattleToday)
+	}
+
+	publisher, err := newPublisher(ctx, publishDir, shouldUpload)
 	if err != nil {
-		_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: could not render page: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
+		_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: could not set up publishing: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
 		return err
 	}
 
-	jsonData, err := renderjson.RenderJSON(eventResults, seattleToday)
+	log.Info().Msg("rendering page")
+	artifacts, skipped, err := renderArtifacts(ctx, eventResults, seattleToday, publisher)
 	if err != nil {
-		_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: could not render JSON: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
+		_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
 		return err
 	}
+	if len(skipped) > 0 {
+		_ = notifier.Notify(ctx, skippedMessage(skipped), notifier.PriorityHigh, notifier.EmojiWarning)
+	}
 
-	log.Info().Msg("render complete")
+	log.Info().Int("artifact_count", len(artifacts)).Msg("render complete")
 
-	runningInDefaultMode := os.Getenv("_HANDLER") != "" && triggeredByEventBridge
-	shouldUploadAnyway := event.Upload || (!triggeredByEventBridge && event.Upload)
+	runID := uploader.NewRunID(time.Now())
 
-	if runningInDefaultMode || shouldUploadAnyway {
-		log.Info().Msg("beginning upload")
-		err = uploader.Upload(ctx, page, jsonData, event.InvalidateAll)
+	if publishDir != "" {
+		log.Info().Str("publish_dir", publishDir).Str("run_id", runID).Msg("publishing to local directory")
+		err = uploader.Publish(ctx, publisher, runID, artifacts, event.InvalidateAll, uploader.DefaultKeepReleases)
+		if err != nil {
+			return err
+		}
+	} else if shouldUpload {
+		log.Info().Str("run_id", runID).Msg("beginning upload")
+		err = uploader.Publish(ctx, publisher, runID, artifacts, event.InvalidateAll, uploader.DefaultKeepReleases)
 		if err != nil {
 			_ = notifier.Notify(ctx, fmt.Sprintf("ERROR: upload page: %s", err.Error()), notifier.PriorityHigh, notifier.EmojiSiren)
 			return err
 		}
 
 		log.Info().Msg("storing in google calendar")
-		err = insertToGoogleCalendar(ctx, eventResults.TodayEvent)
+		statusChanges, err := insertToGoogleCalendar(ctx, eventResults.TodayEvent)
 		if err != nil {
 			log.Warn().Err(err).Msg("could not insert in to google calendar; ignoring")
 		}
+		if len(statusChanges) > 0 {
+			log.Info().Int("status_changes", len(statusChanges)).Msg("found events with changed status")
+			_ = notifier.Notify(ctx, statusChangeMessage(statusChanges), notifier.PriorityHigh, notifier.EmojiWarning)
+		}
 
 		log.Info().Msg("upload complete")
 	} else {
 		log.Warn().Msg("detected running locally, not uploading")
-		fmt.Printf("%s\n----------\n%s\n", string(jsonData), string(page))
+		fmt.Println(localOutput(event.Format, artifacts, eventResults, seattleToday))
 	}
 
 	log.Info().Msg("all in a day's work...")
 
-	notificationMessage := fmt.Sprintf("Everything worked! Found %d game(s) for %s and %d game(s) for %s",
+	notificationMessage := fmt.Sprintf("Everything worked! Found %d game(s) for %s and %d game(s) for %s (run %s)",
 		len(eventResults.TodayEvent),
 		seattleToday.Format("2006-01-02"),
 		len(eventResults.TomorrowEvents),
-		seattleTomorrow.Format("2006-01-02"))
+		seattleTomorrow.Format("2006-01-02"),
+		runID)
 
 	err = notifier.Notify(ctx, notificationMessage, notifier.PriorityDefault, notifier.EmojiParty)
 	if err != nil {
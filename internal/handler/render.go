@@ -0,0 +1,435 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/i18n"
+	"github.com/lthummus/seattle-sports-today/internal/renderarchive"
+	"github.com/lthummus/seattle-sports-today/internal/renderbadge"
+	"github.com/lthummus/seattle-sports-today/internal/renderfeed"
+	"github.com/lthummus/seattle-sports-today/internal/renderhtml"
+	"github.com/lthummus/seattle-sports-today/internal/renderics"
+	"github.com/lthummus/seattle-sports-today/internal/renderimage"
+	"github.com/lthummus/seattle-sports-today/internal/renderjson"
+	"github.com/lthummus/seattle-sports-today/internal/rendersitemap"
+	"github.com/lthummus/seattle-sports-today/internal/rendertext"
+	"github.com/lthummus/seattle-sports-today/internal/site"
+	"github.com/lthummus/seattle-sports-today/internal/uploader"
+)
+
+const (
+	indexKey = "index.html"
+	jsonKey  = "todays_events.json"
+	icsKey   = "todays_events.ics"
+
+	contentTypeHTML = "text/html"
+	contentTypeJSON = "application/json"
+	contentTypeICS  = "text/calendar; charset=utf-8"
+	contentTypeAtom = "application/atom+xml"
+	contentTypePNG  = "image/png"
+	contentTypeSVG  = "image/svg+xml"
+	contentTypeText = "text/plain; charset=utf-8"
+	contentTypeXML  = "application/xml"
+
+	contentTypeSchema = "application/schema+json"
+)
+
+func venuePageKey(v *events.Venue) string {
+	return fmt.Sprintf("venue/%s/index.html", v.Slug)
+}
+
+func venueJSONKey(v *events.Venue) string {
+	return fmt.Sprintf("venue/%s.json", v.Slug)
+}
+
+func venueImageKey(v *events.Venue) string {
+	return fmt.Sprintf("venue/%s/%s", v.Slug, renderimage.OGKey)
+}
+
+func venueLinks() []renderhtml.NavLink {
+	links := make([]renderhtml.NavLink, len(events.Venues))
+	for i, curr := range events.Venues {
+		links[i] = renderhtml.NavLink{
+			Name: curr.Name,
+			URL:  "/" + venuePageKey(curr),
+		}
+	}
+	return links
+}
+
+// renderVenueArtifacts renders a page, preview image and JSON for every venue in the catalog with only the events at that venue
+func renderVenueArtifacts(eventResults *events.EventResults, seattleToday time.Time) ([]uploader.Artifact, error) {
+	var artifacts []uploader.Artifact
+
+	for _, venue := range events.Venues {
+		venueResults := eventResults.Filter(func(e *events.Event) bool {
+			return e.IsAt(venue)
+		})
+
+		page, err := renderhtml.RenderPage(venueResults, seattleToday, renderhtml.PageOptions{
+			Title:    fmt.Sprintf("Is there anything at %s today?", venue.Name),
+			URL:      site.PageURL(venuePageKey(venue)),
+			ImageKey: venueImageKey(venue),
+			Theme:    pageTheme,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not render page for %s: %w", venue.Name, err)
+		}
+
+		image, err := renderimage.RenderPreviewImage(venue.Name, venueResults, seattleToday)
+		if err != nil {
+			return nil, fmt.Errorf("could not render preview image for %s: %w", venue.Name, err)
+		}
+
+		jsonData, err := renderjson.RenderJSONV2(venueResults, seattleToday)
+		if err != nil {
+			return nil, fmt.Errorf("could not render JSON for %s: %w", venue.Name, err)
+		}
+
+		artifacts = append(artifacts,
+			uploader.Artifact{Key: venuePageKey(venue), ContentType: contentTypeHTML, Contents: page},
+			uploader.Artifact{Key: venueImageKey(venue), ContentType: contentTypePNG, Contents: image},
+			uploader.Artifact{Key: venueJSONKey(venue), ContentType: contentTypeJSON, Contents: jsonData},
+		)
+	}
+
+	return artifacts, nil
+}
+
+func teamPageKey(t *events.Team) string {
+	return fmt.Sprintf("team/%s/index.html", t.Slug)
+}
+
+func teamJSONKey(t *events.Team) string {
+	return fmt.Sprintf("team/%s.json", t.Slug)
+}
+
+func teamICSKey(t *events.Team) string {
+	return fmt.Sprintf("team/%s.ics", t.Slug)
+}
+
+func teamImageKey(t *events.Team) string {
+	return fmt.Sprintf("team/%s/%s", t.Slug, renderimage.OGKey)
+}
+
+func teamLinks() []renderhtml.NavLink {
+	links := make([]renderhtml.NavLink, len(events.Teams))
+	for i, curr := range events.Teams {
+		links[i] = renderhtml.NavLink{
+			Name: curr.Name,
+			URL:  "/" + teamPageKey(curr),
+		}
+	}
+	return links
+}
+
+// renderTeamArtifacts renders a page, preview image, JSON and calendar feed for every team in the catalog with only that team's home
+// games
+func renderTeamArtifacts(eventResults *events.EventResults, seattleToday time.Time) ([]uploader.Artifact, error) {
+	var artifacts []uploader.Artifact
+
+	for _, team := range events.Teams {
+		teamResults := eventResults.Filter(func(e *events.Event) bool {
+			return e.IsHomeGameFor(team)
+		})
+
+		page, err := renderhtml.RenderPage(teamResults, seattleToday, renderhtml.PageOptions{
+			Title:    fmt.Sprintf("Is there a %s home game today?", team.Name),
+			URL:      site.PageURL(teamPageKey(team)),
+			ImageKey: teamImageKey(team),
+			Theme:    pageTheme,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not render page for %s: %w", team.Name, err)
+		}
+
+		image, err := renderimage.RenderPreviewImage(fmt.Sprintf("%s home games", team.Name), teamResults, seattleToday)
+		if err != nil {
+			return nil, fmt.Errorf("could not render preview image for %s: %w", team.Name, err)
+		}
+
+		jsonData, err := renderjson.RenderJSONV2(teamResults, seattleToday)
+		if err != nil {
+			return nil, fmt.Errorf("could not render JSON for %s: %w", team.Name, err)
+		}
+
+		icsData := renderics.RenderTeamICS(team.Name, teamResults, seattleToday)
+
+		artifacts = append(artifacts,
+			uploader.Artifact{Key: teamPageKey(team), ContentType: contentTypeHTML, Contents: page},
+			uploader.Artifact{Key: teamImageKey(team), ContentType: contentTypePNG, Contents: image},
+			uploader.Artifact{Key: teamJSONKey(team), ContentType: contentTypeJSON, Contents: jsonData},
+			uploader.Artifact{Key: teamICSKey(team), ContentType: contentTypeICS, Contents: icsData},
+		)
+	}
+
+	return artifacts, nil
+}
+
+// renderTranslatedPages renders the main page in every locale other than the default, each under its own prefix
+func renderTranslatedPages(eventResults *events.EventResults, seattleToday time.Time) ([]uploader.Artifact, error) {
+	var artifacts []uploader.Artifact
+
+	for _, locale := range i18n.Locales {
+		if locale == i18n.Default {
+			continue
+		}
+
+		page, err := renderhtml.RenderPage(eventResults, seattleToday, renderhtml.PageOptions{
+			PreviousDayURL: renderarchive.DayURL(seattleToday.AddDate(0, 0, -1)),
+			VenueLinks:     venueLinks(),
+			TeamLinks:      teamLinks(),
+			Theme:          pageTheme,
+			Locale:         locale,
+			Translations:   i18n.Locales,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not render %s page: %w", locale.Code, err)
+		}
+
+		artifacts = append(artifacts, uploader.Artifact{Key: locale.IndexKey(), ContentType: contentTypeHTML, Contents: page})
+	}
+
+	return artifacts, nil
+}
+
+// fetchPrevious gets a previously published artifact for renderers that build on what is already live. It returns nil
+// with no error if nothing has been published at key yet. When we aren't publishing anywhere, we don't touch the live
+// site at all and those renderers just start fresh.
+func fetchPrevious(ctx context.Context, key string, publisher uploader.Publisher) ([]byte, error) {
+	if publisher == nil {
+		return nil, nil
+	}
+
+	return uploader.Fetch(ctx, publisher, key)
+}
+
+// renderFeed renders the feed on top of the one that is already published
+func renderFeed(ctx context.Context, eventResults *events.EventResults, seattleToday time.Time, publisher uploader.Publisher) ([]byte, error) {
+	previous, err := fetchPrevious(ctx, renderfeed.FeedKey, publisher)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderfeed.RenderFeed(previous, eventResults, seattleToday)
+}
+
+// renderMonthIndex renders the month index on top of the month data that is already published
+func renderMonthIndex(ctx context.Context, eventResults *events.EventResults, seattleToday time.Time, publisher uploader.Publisher) ([]byte, []byte, error) {
+	previous, err := fetchPrevious(ctx, renderarchive.MonthDataKey(seattleToday), publisher)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return renderarchive.RenderMonthIndex(previous, eventResults, seattleToday)
+}
+
+// nextDayURL links a day's archive page to the next day's, but only if that page has been published. Normally it
+// hasn't, since we publish today's page today, so this only links pages that are published again later.
+func nextDayURL(ctx context.Context, seattleToday time.Time, publisher uploader.Publisher) string {
+	seattleTomorrow := seattleToday.AddDate(0, 0, 1)
+
+	next, err := fetchPrevious(ctx, renderarchive.DayKey(seattleTomorrow)+".html", publisher)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not check for the next day's archive page, not linking to it")
+		return ""
+	}
+	if next == nil {
+		return ""
+	}
+
+	return renderarchive.DayURL(seattleTomorrow)
+}
+
+// skippedArtifact is an artifact that builds on what is live, and was left alone because that couldn't be read. It
+// won't update again until someone repairs or deletes the live copy.
+type skippedArtifact struct {
+	Key string
+	Err error
+}
+
+// renderArtifacts renders every file that gets published for a run, along with any that had to be skipped
+func renderArtifacts(ctx context.Context, eventResults *events.EventResults, seattleToday time.Time, publisher uploader.Publisher) ([]uploader.Artifact, []skippedArtifact, error) {
+	seattleYesterday := seattleToday.AddDate(0, 0, -1)
+
+	page, err := renderhtml.RenderPage(eventResults, seattleToday, renderhtml.PageOptions{
+		PreviousDayURL: renderarchive.DayURL(seattleYesterday),
+		VenueLinks:     venueLinks(),
+		TeamLinks:      teamLinks(),
+		Theme:          pageTheme,
+		Translations:   i18n.Locales,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render page: %w", err)
+	}
+
+	translatedArtifacts, err := renderTranslatedPages(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archivePage, err := renderhtml.RenderPage(eventResults, seattleToday, renderhtml.PageOptions{
+		PreviousDayURL: renderarchive.DayURL(seattleYesterday),
+		NextDayURL:     nextDayURL(ctx, seattleToday, publisher),
+		URL:            site.PageURL(renderarchive.DayKey(seattleToday) + ".html"),
+		Archive:        true,
+		Theme:          pageTheme,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render archive page: %w", err)
+	}
+
+	jsonData, err := renderjson.RenderJSON(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render JSON: %w", err)
+	}
+
+	jsonV2Data, err := renderjson.RenderJSONV2(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render v2 JSON: %w", err)
+	}
+
+	schemaData, err := renderjson.RenderSchema()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render JSON schema: %w", err)
+	}
+
+	icsData := renderics.RenderICS(eventResults, seattleToday)
+
+	ogImage, err := renderimage.RenderOGImage(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render preview image: %w", err)
+	}
+
+	badge, err := renderbadge.RenderBadge(eventResults)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render badge: %w", err)
+	}
+
+	widget, err := renderbadge.RenderWidget(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not render widget: %w", err)
+	}
+
+	venueArtifacts, err := renderVenueArtifacts(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teamArtifacts, err := renderTeamArtifacts(eventResults, seattleToday)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dayKey := renderarchive.DayKey(seattleToday)
+
+	artifacts := []uploader.Artifact{
+		{Key: indexKey, ContentType: contentTypeHTML, Contents: page},
+		{Key: jsonKey, ContentType: contentTypeJSON, Contents: jsonData},
+		{Key: renderjson.V2Key, ContentType: contentTypeJSON, Contents: jsonV2Data},
+		{Key: renderjson.SchemaKey, ContentType: contentTypeSchema, Contents: schemaData},
+		{Key: icsKey, ContentType: contentTypeICS, Contents: icsData},
+		{Key: rendertext.TextKey, ContentType: contentTypeText, Contents: rendertext.RenderText(eventResults, seattleToday)},
+		{Key: renderimage.OGKey, ContentType: contentTypePNG, Contents: ogImage},
+		{Key: renderbadge.BadgeKey, ContentType: contentTypeSVG, Contents: badge},
+		{Key: renderbadge.WidgetKey, ContentType: contentTypeHTML, Contents: widget},
+		{Key: dayKey + ".html", ContentType: contentTypeHTML, Contents: archivePage},
+		{Key: dayKey + ".json", ContentType: contentTypeJSON, Contents: jsonData},
+	}
+
+	// the month index builds on the month data that is live. Same as the feed, if we can't read that we leave both
+	// alone instead of dropping every other day in the month
+	var skipped []skippedArtifact
+	monthData, monthPage, err := renderMonthIndex(ctx, eventResults, seattleToday, publisher)
+	if err != nil {
+		log.Error().Err(err).Str("key", renderarchive.MonthDataKey(seattleToday)).Msg("could not render month index, leaving the live one alone")
+		skipped = append(skipped, skippedArtifact{Key: renderarchive.MonthDataKey(seattleToday), Err: err})
+	} else {
+		artifacts = append(artifacts,
+			uploader.Artifact{Key: renderarchive.MonthDataKey(seattleToday), ContentType: contentTypeJSON, Contents: monthData},
+			uploader.Artifact{Key: renderarchive.MonthIndexKey(seattleToday), ContentType: contentTypeHTML, Contents: monthPage},
+		)
+	}
+
+	// the feed builds on the one that is live. If we can't read that, we leave it alone instead of replacing every day
+	// in it with just today
+	feedData, err := renderFeed(ctx, eventResults, seattleToday, publisher)
+	if err != nil {
+		log.Error().Err(err).Str("key", renderfeed.FeedKey).Msg("could not render feed, leaving the live one alone")
+		skipped = append(skipped, skippedArtifact{Key: renderfeed.FeedKey, Err: err})
+	} else {
+		artifacts = append(artifacts, uploader.Artifact{Key: renderfeed.FeedKey, ContentType: contentTypeAtom, Contents: feedData})
+	}
+
+	artifacts = append(artifacts, translatedArtifacts...)
+	artifacts = append(artifacts, venueArtifacts...)
+	artifacts = append(artifacts, teamArtifacts...)
+
+	// the sitemap keeps every archive day that is live, so it is left alone too if we can't read it
+	sitemap, err := renderSitemap(ctx, artifacts, seattleToday, publisher)
+	if err != nil {
+		log.Error().Err(err).Str("key", rendersitemap.SitemapKey).Msg("could not render sitemap, leaving the live one alone")
+		skipped = append(skipped, skippedArtifact{Key: rendersitemap.SitemapKey, Err: err})
+	} else {
+		artifacts = append(artifacts, uploader.Artifact{Key: rendersitemap.SitemapKey, ContentType: contentTypeXML, Contents: sitemap})
+	}
+
+	artifacts = append(artifacts,
+		uploader.Artifact{Key: rendersitemap.RobotsKey, ContentType: contentTypeText, Contents: rendersitemap.RenderRobots()},
+	)
+
+	return artifacts, skipped, nil
+}
+
+// renderSitemap renders a sitemap with every page in artifacts, on top of the one that is already published
+func renderSitemap(ctx context.Context, artifacts []uploader.Artifact, seattleToday time.Time, publisher uploader.Publisher) ([]byte, error) {
+	var pages []string
+	for _, curr := range artifacts {
+		// the widget is only meant to be seen inside other pages
+		if curr.ContentType == contentTypeHTML && curr.Key != renderbadge.WidgetKey {
+			pages = append(pages, curr.Key)
+		}
+	}
+
+	previous, err := fetchPrevious(ctx, rendersitemap.SitemapKey, publisher)
+	if err != nil {
+		return nil, err
+	}
+
+	return rendersitemap.RenderSitemap(previous, pages, seattleToday)
+}
+
+// OutputFormats are the formats that can be printed when running locally
+var OutputFormats = []string{"text", "markdown", "html", "json"}
+
+// localOutput is what we print when running locally in the given format
+func localOutput(format string, artifacts []uploader.Artifact, eventResults *events.EventResults, seattleToday time.Time) string {
+	switch format {
+	case "text":
+		return string(findArtifact(artifacts, rendertext.TextKey))
+	case "markdown":
+		// markdown doesn't get published, so it is rendered on demand
+		return string(rendertext.RenderMarkdown(eventResults, seattleToday))
+	case "html":
+		return string(findArtifact(artifacts, indexKey))
+	case "json":
+		return string(findArtifact(artifacts, jsonKey))
+	default:
+		return fmt.Sprintf("%s\n----------\n%s", string(findArtifact(artifacts, jsonKey)), string(findArtifact(artifacts, indexKey)))
+	}
+}
+
+// findArtifact returns the contents of the artifact with the given key, or nil if there isn't one
+func findArtifact(artifacts []uploader.Artifact, key string) []byte {
+	for _, curr := range artifacts {
+		if curr.Key == key {
+			return curr.Contents
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/renderarchive"
+	"github.com/lthummus/seattle-sports-today/internal/renderfeed"
+	"github.com/lthummus/seattle-sports-today/internal/rendersitemap"
+	"github.com/lthummus/seattle-sports-today/internal/uploader"
+)
+
+func TestRenderArtifacts_Publish(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 3, 14, 0, 0, events.SeattleTimeZone)
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				TeamName:  "Seattle Mariners",
+				Opponent:  "Houston Astros",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				RawTime:   start.Unix(),
+			},
+		},
+	}
+
+	publisher := uploader.NewMemory()
+
+	artifacts, _, err := renderArtifacts(context.Background(), results, seattleToday, publisher)
+	require.NoError(t, err)
+	require.NoError(t, uploader.Upload(context.Background(), publisher, artifacts, false))
+
+	for _, key := range []string{"index.html", "es/index.html", "todays_events.json", "archive/2026/05/02.html", "team/mariners/index.html", "team/mariners/og.png", "venue/t-mobile-park/index.html", "venue/t-mobile-park/og.png"} {
+		_, ok := publisher.Object(key)
+		assert.True(t, ok, "%s wasn't published", key)
+	}
+
+	index, ok := publisher.Object("index.html")
+	require.True(t, ok)
+	assert.Equal(t, contentTypeHTML, index.ContentType)
+	assert.Equal(t, uploader.DefaultCachePolicy, index.CacheControl)
+	assert.Contains(t, string(index.Contents), "Seattle Mariners are playing against the Houston Astros")
+
+	// venue and team pages are previewed with their own answer, not the main page's
+	teamPage, ok := publisher.Object("team/mariners/index.html")
+	require.True(t, ok)
+	assert.Contains(t, string(teamPage.Contents), `<meta property="og:image" content="https://isthereaseattlehomegametoday.com/team/mariners/og.png?d=20260502">`)
+
+	venuePage, ok := publisher.Object("venue/lumen-field/index.html")
+	require.True(t, ok)
+	assert.Contains(t, string(venuePage.Contents), `<meta property="og:title" content="Is there anything at Lumen Field today? NO">`)
+	assert.Contains(t, string(venuePage.Contents), `<meta property="og:image" content="https://isthereaseattlehomegametoday.com/venue/lumen-field/og.png?d=20260502">`)
+
+	robots, ok := publisher.Object(rendersitemap.RobotsKey)
+	require.True(t, ok)
+	assert.Equal(t, rendersitemap.RenderRobots(), robots.Contents)
+
+	// everything changed, which is too much to invalidate path by path
+	assert.Equal(t, [][]string{{"/*"}}, publisher.Invalidations())
+
+	// the next day builds on what was published
+	artifacts, _, err = renderArtifacts(context.Background(), &events.EventResults{}, seattleToday.AddDate(0, 0, 1), publisher)
+	require.NoError(t, err)
+	require.NoError(t, uploader.Upload(context.Background(), publisher, artifacts, false))
+
+	sitemap, ok := publisher.Object(rendersitemap.SitemapKey)
+	require.True(t, ok)
+	assert.Contains(t, string(sitemap.Contents), "<loc>https://isthereaseattlehomegametoday.com/archive/2026/05/02.html</loc>")
+	assert.Contains(t, string(sitemap.Contents), "<loc>https://isthereaseattlehomegametoday.com/archive/2026/05/03.html</loc>")
+}
+
+// unreadablePublisher can't read some keys, like S3 when the function isn't allowed to
+type unreadablePublisher struct {
+	*uploader.Memory
+	unreadable []string
+}
+
+func (p unreadablePublisher) Get(ctx context.Context, key string) ([]byte, error) {
+	if slices.Contains(p.unreadable, key) {
+		return nil, errors.New("AccessDenied")
+	}
+	return p.Memory.Get(ctx, key)
+}
+
+func TestRenderArtifacts_UnreadablePrevious(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 3, 14, 0, 0, events.SeattleTimeZone)
+	publisher := unreadablePublisher{Memory: uploader.NewMemory(), unreadable: []string{renderfeed.FeedKey, renderarchive.MonthDataKey(seattleToday), rendersitemap.SitemapKey}}
+
+	artifacts, skipped, err := renderArtifacts(context.Background(), &events.EventResults{}, seattleToday, publisher)
+	require.NoError(t, err)
+
+	// someone gets told, since they won't update again until the live copy is fixed
+	var skippedKeys []string
+	for _, curr := range skipped {
+		skippedKeys = append(skippedKeys, curr.Key)
+	}
+	assert.Equal(t, []string{renderarchive.MonthDataKey(seattleToday), renderfeed.FeedKey, rendersitemap.SitemapKey}, skippedKeys)
+	assert.Contains(t, skippedMessage(skipped), "feed.xml: ")
+
+	// what is live is left alone, and everything else is still published
+	assert.Nil(t, findArtifact(artifacts, renderfeed.FeedKey))
+	assert.Nil(t, findArtifact(artifacts, renderarchive.MonthDataKey(seattleToday)))
+	assert.Nil(t, findArtifact(artifacts, renderarchive.MonthIndexKey(seattleToday)))
+	assert.Nil(t, findArtifact(artifacts, rendersitemap.SitemapKey))
+	assert.NotNil(t, findArtifact(artifacts, indexKey))
+	assert.NotNil(t, findArtifact(artifacts, renderarchive.DayKey(seattleToday)+".html"))
+
+	// nothing published yet isn't the same as not being able to read it
+	artifacts, skipped, err = renderArtifacts(context.Background(), &events.EventResults{}, seattleToday, uploader.NewMemory())
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.NotNil(t, findArtifact(artifacts, renderfeed.FeedKey))
+	assert.NotNil(t, findArtifact(artifacts, renderarchive.MonthDataKey(seattleToday)))
+	assert.NotNil(t, findArtifact(artifacts, rendersitemap.SitemapKey))
+}
+
+func TestRenderArtifacts_NextDayLink(t *testing.T) {
+	ctx := context.Background()
+	seattleToday := time.Date(2026, time.May, 2, 3, 14, 0, 0, events.SeattleTimeZone)
+	dayKey := renderarchive.DayKey(seattleToday) + ".html"
+	publisher := uploader.NewMemory()
+
+	// the next day hasn't been published yet, so there is nothing to link to
+	artifacts, _, err := renderArtifacts(ctx, &events.EventResults{}, seattleToday, publisher)
+	require.NoError(t, err)
+	require.NoError(t, uploader.Upload(ctx, publisher, artifacts, false))
+	assert.NotContains(t, string(findArtifact(artifacts, dayKey)), `rel="next"`)
+
+	artifacts, _, err = renderArtifacts(ctx, &events.EventResults{}, seattleToday.AddDate(0, 0, 1), publisher)
+	require.NoError(t, err)
+	require.NoError(t, uploader.Upload(ctx, publisher, artifacts, false))
+
+	// publishing the day again once the next one is up links them
+	artifacts, _, err = renderArtifacts(ctx, &events.EventResults{}, seattleToday, publisher)
+	require.NoError(t, err)
+	assert.Contains(t, string(findArtifact(artifacts, dayKey)), `href="/archive/2026/05/03.html" rel="next"`)
+}
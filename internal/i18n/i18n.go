@@ -0,0 +1,206 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+// The message catalogs are JSON files of message key to fmt format string. Format strings use explicit argument
+// indexes (%[1]s) so translations can put things in a different order. The arguments for each kind of message are:
+//
+//   - event.game and summary.game: team, opponent, venue, start time (event only)
+//   - event.doubleheader_first: team, opponent, venue, start time
+//   - event.doubleheader_game: team, game number, opponent, venue, start time
+//   - summary.doubleheader_game: team, opponent, venue, game number, game count
+//   - event.at and summary.at: event name, venue, start time (event only)
+//   - event.at_multi_day and summary.at_multi_day: event name, venue, day number, day count, start time (event only)
+//   - format.date: weekday name, month name, day of month, year, month number
+//   - format.date_short: weekday name, full month name, day of month, month number
+//
+// format.time is a Go time layout rather than a format string.
+//
+//go:embed catalogs/*.json
+var catalogs embed.FS
+
+// Locale is a language we publish the page in
+type Locale struct {
+	// Code is the BCP 47 tag, used for the lang and hreflang attributes
+	Code string
+
+	// Name is the name of the language, in that language
+	Name string
+
+	// Path is the prefix of this locale's pages. It is empty for the default locale, so English stays at the root of
+	// the site.
+	Path string
+
+	catalog  string
+	messages map[string]string
+}
+
+var (
+	// Default is the locale of the main page
+	Default = &Locale{Code: "en", Name: "English", Path: "", catalog: "en"}
+
+	// Locales is every locale we publish, starting with Default
+	Locales = []*Locale{
+		Default,
+		{Code: "es", Name: "Español", Path: "es/", catalog: "es"},
+		{Code: "zh-Hans", Name: "中文", Path: "zh/", catalog: "zh"},
+		{Code: "vi", Name: "Tiếng Việt", Path: "vi/", catalog: "vi"},
+	}
+)
+
+func init() {
+	for _, curr := range Locales {
+		contents, err := catalogs.ReadFile(fmt.Sprintf("catalogs/%s.json", curr.catalog))
+		if err != nil {
+			log.Fatal().Err(err).Str("locale", curr.Code).Msg("could not read message catalog")
+		}
+
+		err = json.Unmarshal(contents, &curr.messages)
+		if err != nil {
+			log.Fatal().Err(err).Str("locale", curr.Code).Msg("could not parse message catalog")
+		}
+	}
+}
+
+// IndexKey is where this locale's copy of the main page is published
+func (l *Locale) IndexKey() string {
+	return l.Path + "index.html"
+}
+
+// URLPath is the path of this locale's copy of the main page. Only the default locale's is a directory, since CloudFront
+// only serves index.html for the root of the site.
+func (l *Locale) URLPath() string {
+	if l.Path == "" {
+		return "/"
+	}
+	return "/" + l.IndexKey()
+}
+
+// T looks up the message with the given key and formats it with args. Messages missing from a translation fall back to
+// English, and messages missing entirely come back as the key so they're easy to spot.
+func (l *Locale) T(key string, args ...any) string {
+	message, ok := l.messages[key]
+	if !ok {
+		message, ok = Default.messages[key]
+	}
+	if !ok {
+		log.Warn().Str("locale", l.Code).Str("key", key).Msg("missing message")
+		return key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// FormatDate formats a date the way people who speak this language expect (e.g. "Saturday May  2, 2026" or
+// "sábado 2 de mayo de 2026")
+func (l *Locale) FormatDate(t time.Time) string {
+	return l.T("format.date",
+		l.T(fmt.Sprintf("weekday.%d", t.Weekday())),
+		l.T(fmt.Sprintf("month.%d", t.Month())),
+		t.Day(),
+		t.Year(),
+		int(t.Month()))
+}
+
+// FormatShortDate formats a date without the year, for places that are already about today (e.g. "Saturday, May 2" or
+// "sábado 2 de mayo")
+func (l *Locale) FormatShortDate(t time.Time) string {
+	return l.T("format.date_short",
+		l.T(fmt.Sprintf("weekday.%d", t.Weekday())),
+		l.T(fmt.Sprintf("month_long.%d", t.Month())),
+		t.Day(),
+		int(t.Month()))
+}
+
+// FormatTime formats a time of day (e.g. "7:10 PM" or "19:10")
+func (l *Locale) FormatTime(t time.Time) string {
+	return t.Format(l.T("format.time"))
+}
+
+// EventTime is the start time of the event. Sources already give us the time formatted for English, and it might be
+// something like TBA, so that is used as is when we can.
+func (l *Locale) EventTime(e *events.Event) string {
+	switch {
+	case e.LocalTime == "TBA":
+		return l.T("time.tba")
+	case e.LocalTime != "" && l == Default:
+		return e.LocalTime
+	default:
+		return l.FormatTime(e.StartTime())
+	}
+}
+
+// Describe is Event.String() in this locale. Events that only have a prewritten description (like hand entered special
+// events) can't be translated, so they stay in English.
+func (l *Locale) Describe(e *events.Event) string {
+	return l.DescribeAt(e, l.EventTime(e))
+}
+
+// DescribeAt is Describe with when in place of the start time. This is for callers that want to mark up the time, like
+// the page wrapping it in a <time> element.
+func (l *Locale) DescribeAt(e *events.Event, when string) string {
+	session := e.Session
+	switch {
+	case e.Name != "" && session != nil && session.Kind == events.SessionKindMultiDay:
+		return l.T("event.at_multi_day", e.Name, e.Venue, session.Number, session.Count, when)
+	case e.Name != "":
+		return l.T("event.at", e.Name, e.Venue, when)
+	case e.RawDescription != "":
+		return e.RawDescription
+	case session != nil && session.Kind == events.SessionKindDoubleheader && session.Number == 1:
+		return l.T("event.doubleheader_first", e.TeamName, e.Opponent, e.Venue, when)
+	case session != nil && session.Kind == events.SessionKindDoubleheader:
+		return l.T("event.doubleheader_game", e.TeamName, session.Number, e.Opponent, e.Venue, when)
+	default:
+		return l.T("event.game", e.TeamName, e.Opponent, e.Venue, when)
+	}
+}
+
+// Summarize is Event.CalendarSummary() in this locale
+func (l *Locale) Summarize(e *events.Event) string {
+	session := e.Session
+	switch {
+	case e.Name != "" && session != nil && session.Kind == events.SessionKindMultiDay:
+		return l.T("summary.at_multi_day", e.Name, e.Venue, session.Number, session.Count)
+	case e.Name != "":
+		return l.T("summary.at", e.Name, e.Venue)
+	case e.ShortDescription != "":
+		return e.ShortDescription
+	case e.RawDescription != "":
+		return e.RawDescription
+	case session != nil && session.Kind == events.SessionKindDoubleheader:
+		return l.T("summary.doubleheader_game", e.TeamName, e.Opponent, e.Venue, session.Number, session.Count)
+	default:
+		return l.T("summary.game", e.TeamName, e.Opponent, e.Venue)
+	}
+}
+
+// StatusLabel is Event.StatusLabel() in this locale
+func (l *Locale) StatusLabel(e *events.Event) string {
+	if e.StatusLabel() == "" {
+		return ""
+	}
+	return l.T(fmt.Sprintf("status.%s", e.Status))
+}
+
+// LinkLabel is Link.Label() in this locale
+func (l *Locale) LinkLabel(link events.Link) string {
+	switch link.Kind {
+	case events.LinkKindTickets, events.LinkKindTeam:
+		return l.T(fmt.Sprintf("link.%s", link.Kind))
+	default:
+		return l.T("link.info")
+	}
+}
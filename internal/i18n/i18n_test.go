@@ -0,0 +1,102 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+var start = time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+func testEvents() []*events.Event {
+	return []*events.Event{
+		{TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "7:10 PM", RawTime: start.Unix()},
+		{TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "1:10 PM", RawTime: start.Unix(),
+			Session: &events.Session{Kind: events.SessionKindDoubleheader, Number: 1, Count: 2}},
+		{TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "7:10 PM", RawTime: start.Unix(),
+			Session: &events.Session{Kind: events.SessionKindDoubleheader, Number: 2, Count: 2}},
+		{Name: "Some Band", Venue: "WAMU Theater", LocalTime: "7:10 PM", RawTime: start.Unix(),
+			ShortDescription: "Some Band is at WAMU Theater", RawDescription: "Some Band is at WAMU Theater. It starts at 7:10 PM"},
+		{Name: "Some Festival", Venue: "Lumen Field", LocalTime: "TBA", RawTime: start.Unix(),
+			Session:          &events.Session{Kind: events.SessionKindMultiDay, Number: 2, Count: 3},
+			ShortDescription: "Some Festival is at Lumen Field (day 2 of 3)", RawDescription: "Some Festival is at Lumen Field (day 2 of 3). It starts at TBA"},
+		{ShortDescription: "Something special", RawDescription: "Something special is happening"},
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for _, locale := range Locales {
+		for key := range Default.messages {
+			assert.Contains(t, locale.messages, key, "%s is missing %s", locale.Code, key)
+		}
+		for key, message := range locale.messages {
+			assert.Contains(t, Default.messages, key, "%s has unknown key %s", locale.Code, key)
+			assert.NotEmpty(t, message, "%s has empty %s", locale.Code, key)
+		}
+	}
+}
+
+func TestMessagesFormat(t *testing.T) {
+	for _, locale := range Locales {
+		assert.NotContains(t, locale.FormatDate(start), "%!", locale.Code)
+		assert.NotContains(t, locale.FormatShortDate(start), "%!", locale.Code)
+
+		for _, e := range testEvents() {
+			description := locale.Describe(e)
+			assert.NotContains(t, description, "%!", "%s: %s", locale.Code, description)
+
+			summary := locale.Summarize(e)
+			assert.NotContains(t, summary, "%!", "%s: %s", locale.Code, summary)
+		}
+	}
+}
+
+// English goes through the catalog too, so it has to come out exactly the same as the events package's own strings
+func TestDefaultMatchesEvents(t *testing.T) {
+	for _, e := range testEvents() {
+		assert.Equal(t, e.String(), Default.Describe(e))
+		assert.Equal(t, e.CalendarSummary(), Default.Summarize(e))
+	}
+
+	cancelled := &events.Event{Status: events.StatusCancelled}
+	assert.Equal(t, cancelled.StatusLabel(), Default.StatusLabel(cancelled))
+	assert.Empty(t, Default.StatusLabel(&events.Event{}))
+
+	for _, kind := range []events.LinkKind{events.LinkKindTickets, events.LinkKindTeam, events.LinkKindInfo} {
+		link := events.Link{Kind: kind}
+		assert.Equal(t, link.Label(), Default.LinkLabel(link))
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	es, zh, vi := Locales[1], Locales[2], Locales[3]
+	game := testEvents()[0]
+
+	assert.Equal(t, "Seattle Mariners juegan contra Houston Astros en T-Mobile Park. El partido empieza a las 19:10.", es.Describe(game))
+	assert.Equal(t, "Seattle Mariners在T-Mobile Park对阵Houston Astros。比赛19:10开始。", zh.Describe(game))
+	assert.Equal(t, "Seattle Mariners thi đấu với Houston Astros tại T-Mobile Park. Trận đấu bắt đầu lúc 19:10.", vi.Describe(game))
+
+	assert.Equal(t, "Saturday May  2, 2026", Default.FormatDate(start))
+	assert.Equal(t, "sábado 2 de mayo de 2026", es.FormatDate(start))
+	assert.Equal(t, "2026年5月2日 星期六", zh.FormatDate(start))
+	assert.Equal(t, "Thứ Bảy, ngày 2 tháng 5 năm 2026", vi.FormatDate(start))
+
+	assert.Equal(t, "Saturday, May 2", Default.FormatShortDate(start))
+	assert.Equal(t, "Sunday, February 15", Default.FormatShortDate(time.Date(2026, time.February, 15, 0, 0, 0, 0, events.SeattleTimeZone)))
+	assert.Equal(t, "sábado 2 de mayo", es.FormatShortDate(start))
+	assert.Equal(t, "5月2日 星期六", zh.FormatShortDate(start))
+	assert.Equal(t, "Thứ Bảy, ngày 2 tháng 5", vi.FormatShortDate(start))
+
+	assert.True(t, strings.HasSuffix(es.Describe(testEvents()[4]), "Empieza a las por confirmar."))
+	// hand written descriptions can't be translated
+	assert.Equal(t, "Something special is happening", es.Describe(testEvents()[5]))
+
+	assert.Equal(t, "es/index.html", es.IndexKey())
+	assert.Equal(t, "/es/index.html", es.URLPath())
+	assert.Equal(t, "/", Default.URLPath())
+	assert.Equal(t, "index.html", Default.IndexKey())
+}
@@ -30,9 +30,10 @@ const (
 type Emoji string
 
 const (
-	EmojiNone  Emoji = ""
-	EmojiSiren Emoji = "rotating_light"
-	EmojiParty Emoji = "partying_face"
+	EmojiNone    Emoji = ""
+	EmojiSiren   Emoji = "rotating_light"
+	EmojiParty   Emoji = "partying_face"
+	EmojiWarning Emoji = "warning"
 )
 
 var httpClient = xray.Client(http.DefaultClient)
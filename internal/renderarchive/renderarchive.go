@@ -0,0 +1,142 @@
+package renderarchive
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/site"
+)
+
+//go:embed month.gohtml
+var monthTemplateString string
+
+var monthTemplate *template.Template
+
+func init() {
+	var err error
+	monthTemplate, err = template.New("").Parse(monthTemplateString)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not parse month template")
+	}
+}
+
+// DayKey is where the archived page for the given day lives (without an extension). For example, Feb 14th, 2026 is
+// archive/2026/02/14
+func DayKey(day time.Time) string {
+	return fmt.Sprintf("archive/%s", day.Format("2006/01/02"))
+}
+
+// DayURL is the URL path of the archived page for the given day
+func DayURL(day time.Time) string {
+	return fmt.Sprintf("/%s.html", DayKey(day))
+}
+
+// MonthIndexKey is where the index page for the given day's month lives
+func MonthIndexKey(day time.Time) string {
+	return fmt.Sprintf("archive/%s/index.html", day.Format("2006/01"))
+}
+
+// MonthDataKey is where we keep the data the month index page is built from, so we can add to it on every run
+func MonthDataKey(day time.Time) string {
+	return fmt.Sprintf("archive/%s/index.json", day.Format("2006/01"))
+}
+
+// DayEntry is a single day in the month index
+type DayEntry struct {
+	Date       string `json:"date"`
+	HasGames   bool   `json:"has_games"`
+	EventCount int    `json:"event_count"`
+}
+
+func (d DayEntry) day() time.Time {
+	t, err := time.ParseInLocation("2006-01-02", d.Date, events.SeattleTimeZone)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// URL is the archive page for this day
+func (d DayEntry) URL() string {
+	return DayURL(d.day())
+}
+
+// DisplayDate is the date formatted for people to read
+func (d DayEntry) DisplayDate() string {
+	return d.day().Format("Monday Jan _2")
+}
+
+// MonthIndex is every day we've archived in a month
+type MonthIndex struct {
+	Month string     `json:"month"`
+	Days  []DayEntry `json:"days"`
+}
+
+type monthTemplateParams struct {
+	MonthName string
+	Days      []DayEntry
+}
+
+// parsePrevious reads the month data we published last time. Nothing published yet means this is the first day of the
+// month we've archived, but data we can't read is an error. Starting over would drop every other day in the month.
+func parsePrevious(previous []byte, month string) (*MonthIndex, error) {
+	index := &MonthIndex{Month: month}
+	err := site.DecodePrevious(previous, json.Unmarshal, index)
+	if err != nil {
+		return nil, err
+	}
+	if index.Month != month {
+		return nil, fmt.Errorf("data is for %s, not %s", index.Month, month)
+	}
+
+	return index, nil
+}
+
+// RenderMonthIndex adds the given day to the month's index and renders it. previous is the month data we published last
+// time (which may be nil). It returns the updated month data (to be published at MonthDataKey) and the rendered page (to
+// be published at MonthIndexKey).
+func RenderMonthIndex(previous []byte, results *events.EventResults, seattleToday time.Time) ([]byte, []byte, error) {
+	index, err := parsePrevious(previous, seattleToday.Format("2006-01"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("renderMonthIndex: could not read previous month data: %w", err)
+	}
+
+	today := DayEntry{
+		Date:       seattleToday.Format("2006-01-02"),
+		HasGames:   events.AnyHappening(results.TodayEvent),
+		EventCount: len(events.CollapseSessions(results.TodayEvent)),
+	}
+
+	index.Days = slices.DeleteFunc(index.Days, func(d DayEntry) bool {
+		return d.Date == today.Date
+	})
+	index.Days = append(index.Days, today)
+	slices.SortFunc(index.Days, func(a, b DayEntry) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, nil, fmt.Errorf("renderMonthIndex: could not render data: %w", err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = monthTemplate.Execute(buf, &monthTemplateParams{
+		MonthName: seattleToday.Format("January 2006"),
+		Days:      index.Days,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("renderMonthIndex: could not render: %w", err)
+	}
+
+	return data, buf.Bytes(), nil
+}
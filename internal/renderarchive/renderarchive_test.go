@@ -0,0 +1,66 @@
+package renderarchive
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func TestRenderMonthIndex(t *testing.T) {
+	day1 := time.Date(2026, time.February, 14, 3, 14, 0, 0, events.SeattleTimeZone)
+	day2 := day1.AddDate(0, 0, 1)
+
+	gameDay := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{ID: "espn:1", TeamName: "Seattle Kraken", Opponent: "Vancouver Canucks", Venue: "Climate Pledge Arena"},
+			{ID: "ticketmaster:2", RawDescription: "A concert", Venue: "WAMU Theater", Status: events.StatusPostponed},
+		},
+	}
+
+	data, page, err := RenderMonthIndex(nil, &events.EventResults{}, day2)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), `<a href="/archive/2026/02/15.html">Sunday Feb 15</a>`)
+
+	data, page, err = RenderMonthIndex(data, gameDay, day1)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "Seattle home games in February 2026")
+
+	var index MonthIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+	assert.Equal(t, "2026-02", index.Month)
+	assert.Equal(t, []DayEntry{
+		{Date: "2026-02-14", HasGames: true, EventCount: 2},
+		{Date: "2026-02-15", HasGames: false, EventCount: 0},
+	}, index.Days)
+
+	// a new month has its own key, so it starts with nothing published
+	data, _, err = RenderMonthIndex(nil, gameDay, time.Date(2026, time.March, 1, 3, 14, 0, 0, events.SeattleTimeZone))
+	require.NoError(t, err)
+	var march MonthIndex
+	require.NoError(t, json.Unmarshal(data, &march))
+	assert.Len(t, march.Days, 1)
+}
+
+func TestRenderMonthIndex_UnreadablePrevious(t *testing.T) {
+	day := time.Date(2026, time.February, 14, 3, 14, 0, 0, events.SeattleTimeZone)
+
+	_, _, err := RenderMonthIndex([]byte("this is not json"), &events.EventResults{}, day)
+	assert.ErrorContains(t, err, "could not read previous month data")
+
+	// another month's data under this month's key isn't something to build on either
+	_, _, err = RenderMonthIndex([]byte(`{"month":"2026-01","days":[]}`), &events.EventResults{}, day)
+	assert.ErrorContains(t, err, "data is for 2026-01, not 2026-02")
+}
+
+func TestKeys(t *testing.T) {
+	day := time.Date(2026, time.February, 4, 3, 14, 0, 0, events.SeattleTimeZone)
+	assert.Equal(t, "archive/2026/02/04", DayKey(day))
+	assert.Equal(t, "/archive/2026/02/04.html", DayURL(day))
+	assert.Equal(t, "archive/2026/02/index.html", MonthIndexKey(day))
+	assert.Equal(t, "archive/2026/02/index.json", MonthDataKey(day))
+}
@@ -0,0 +1,116 @@
+package renderbadge
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+const (
+	// BadgeKey and WidgetKey are where the badge and widget are published, next to index.html
+	BadgeKey  = "badge.svg"
+	WidgetKey = "widget.html"
+
+	badgeLabel = "Seattle home game today"
+
+	yesColor = "#4c1"
+	noColor  = "#e05d44"
+
+	// we don't have font metrics for whatever font the viewer ends up with, so approximate every character as the same
+	// width (this is close enough for 11px Verdana) and pad each side
+	charWidth = 7
+	padding   = 10
+)
+
+//go:embed badge.gosvg
+var badgeTemplateString string
+
+//go:embed widget.gohtml
+var widgetTemplateString string
+
+var (
+	badgeTemplate  *template.Template
+	widgetTemplate *template.Template
+)
+
+func init() {
+	var err error
+	badgeTemplate, err = template.New("").Parse(badgeTemplateString)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not parse badge template")
+	}
+
+	widgetTemplate, err = template.New("").Parse(widgetTemplateString)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not parse widget template")
+	}
+}
+
+type badgeParams struct {
+	Label        string
+	Answer       string
+	Color        string
+	LabelWidth   int
+	AnswerWidth  int
+	Width        int
+	LabelCenter  int
+	AnswerCenter int
+}
+
+func textWidth(s string) int {
+	return len([]rune(s))*charWidth + 2*padding
+}
+
+// RenderBadge renders a small SVG badge that says whether there is anything happening in Seattle today
+func RenderBadge(results *events.EventResults) ([]byte, error) {
+	params := badgeParams{
+		Label:  badgeLabel,
+		Answer: "NO",
+		Color:  noColor,
+	}
+	if events.AnyHappening(results.TodayEvent) {
+		params.Answer = "YES"
+		params.Color = yesColor
+	}
+
+	params.LabelWidth = textWidth(params.Label)
+	params.AnswerWidth = textWidth(params.Answer)
+	params.Width = params.LabelWidth + params.AnswerWidth
+	params.LabelCenter = params.LabelWidth / 2
+	params.AnswerCenter = params.LabelWidth + params.AnswerWidth/2
+
+	buf := bytes.NewBuffer(nil)
+	err := badgeTemplate.Execute(buf, &params)
+	if err != nil {
+		return nil, fmt.Errorf("renderBadge: could not render: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type widgetParams struct {
+	HasGames bool
+	Date     string
+	Events   []*events.Event
+}
+
+// RenderWidget renders a small, self-contained HTML page with today's answer and events that can be put in an iframe
+func RenderWidget(results *events.EventResults, seattleToday time.Time) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	err := widgetTemplate.Execute(buf, &widgetParams{
+		HasGames: events.AnyHappening(results.TodayEvent),
+		Date:     seattleToday.Format("Monday Jan _2"),
+		Events:   events.CollapseSessions(results.TodayEvent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("renderWidget: could not render: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
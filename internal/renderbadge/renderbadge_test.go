@@ -0,0 +1,56 @@
+package renderbadge
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func TestRenderBadge(t *testing.T) {
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{{ShortDescription: "Something is happening"}},
+	}
+
+	badge, err := RenderBadge(results)
+	require.NoError(t, err)
+
+	// the badge has to be well formed XML or browsers won't show it
+	var svg struct {
+		XMLName xml.Name `xml:"svg"`
+		Title   string   `xml:"title"`
+	}
+	require.NoError(t, xml.Unmarshal(badge, &svg))
+	assert.Equal(t, "Seattle home game today: YES", svg.Title)
+	assert.Contains(t, string(badge), yesColor)
+
+	cancelled := &events.EventResults{
+		TodayEvent: []*events.Event{{ShortDescription: "Something is happening", Status: events.StatusCancelled}},
+	}
+	badge, err = RenderBadge(cancelled)
+	require.NoError(t, err)
+	require.NoError(t, xml.Unmarshal(badge, &svg))
+	assert.Equal(t, "Seattle home game today: NO", svg.Title)
+}
+
+func TestRenderWidget(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "7:10 PM"},
+			{ShortDescription: "Some <Band> is at WAMU Theater", Status: events.StatusPostponed},
+		},
+	}
+
+	widget, err := RenderWidget(results, seattleToday)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(widget), `<p class="answer yes">YES</p>`)
+	assert.Contains(t, string(widget), "Seattle home games on Saturday May  2")
+	assert.Contains(t, string(widget), "(7:10 PM)")
+	assert.Contains(t, string(widget), `<span class="status">POSTPONED</span> Some &lt;Band&gt; is at WAMU Theater`)
+}
@@ -0,0 +1,195 @@
+package renderfeed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/renderarchive"
+	"github.com/lthummus/seattle-sports-today/internal/site"
+)
+
+const (
+	FeedKey = "feed.xml"
+
+	// MaxEntries is how many days we keep in the feed. Older days fall off the end.
+	MaxEntries = 30
+
+	feedTitle  = "Is there a Seattle home game today?"
+	authorName = "isthereaseattlehomegametoday.com"
+)
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string     `xml:"title"`
+	ID      string     `xml:"id"`
+	Updated string     `xml:"updated"`
+	Links   []atomLink `xml:"link"`
+	Content atomText   `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  *atomPerson `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// entryID is a tag URI (RFC 4151) for the given day. Since it only depends on the date, re-running a day replaces
+// that day's entry instead of adding a second one.
+func entryID(seattleToday time.Time) string {
+	return fmt.Sprintf("tag:isthereaseattlehomegametoday.com,%s:daily", seattleToday.Format("2006-01-02"))
+}
+
+// entryDay is the day the entry with the given ID is for
+func entryDay(id string) (time.Time, bool) {
+	_, rest, _ := strings.Cut(id, ",")
+	date, _, _ := strings.Cut(rest, ":")
+	day, err := time.ParseInLocation("2006-01-02", date, events.SeattleTimeZone)
+	return day, err == nil
+}
+
+// entryLinks links an entry to the archived page for its day, so older entries don't all point at today's page
+func entryLinks(day time.Time) []atomLink {
+	return []atomLink{{Href: site.URL + renderarchive.DayKey(day) + ".html", Rel: "alternate", Type: "text/html"}}
+}
+
+func entryTitle(results *events.EventResults, seattleToday time.Time) string {
+	date := seattleToday.Format("Monday Jan _2, 2006")
+	if !events.AnyHappening(results.TodayEvent) {
+		return fmt.Sprintf("NO: nothing in Seattle on %s", date)
+	}
+
+	count := len(events.CollapseSessions(results.TodayEvent))
+	if count == 1 {
+		return fmt.Sprintf("YES: 1 event in Seattle on %s", date)
+	}
+	return fmt.Sprintf("YES: %d events in Seattle on %s", count, date)
+}
+
+func writeEventLines(sb *strings.Builder, x []*events.Event) {
+	for _, curr := range events.CollapseSessions(x) {
+		sb.WriteString("* ")
+		if label := curr.StatusLabel(); label != "" {
+			sb.WriteString(label)
+			sb.WriteString(": ")
+		}
+		sb.WriteString(curr.String())
+		sb.WriteString("\n")
+	}
+}
+
+func entryContent(results *events.EventResults) string {
+	var sb strings.Builder
+
+	if len(results.TodayEvent) == 0 {
+		sb.WriteString("Nothing is scheduled today.\n")
+	} else {
+		sb.WriteString("Today:\n")
+		writeEventLines(&sb, results.TodayEvent)
+	}
+
+	sb.WriteString("\n")
+
+	if len(results.TomorrowEvents) == 0 {
+		sb.WriteString("Nothing is scheduled tomorrow (yet?).\n")
+	} else {
+		sb.WriteString("Tomorrow:\n")
+		writeEventLines(&sb, results.TomorrowEvents)
+	}
+
+	return sb.String()
+}
+
+// entryTime parses the updated time of an entry. Anything we can't parse sorts to the end so it falls off first.
+func entryTime(e atomEntry) time.Time {
+	t, err := time.Parse(time.RFC3339, e.Updated)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// RenderFeed renders an Atom feed with one entry per day. The entries from the previously published feed (which may be
+// nil) are kept, with today's entry added (or replaced if this day has already been rendered) and only the most recent
+// MaxEntries days kept.
+func RenderFeed(previous []byte, results *events.EventResults, seattleToday time.Time) ([]byte, error) {
+	updated := seattleToday.Format(time.RFC3339)
+	id := entryID(seattleToday)
+
+	var previousFeed atomFeed
+	err := site.DecodePrevious(previous, xml.Unmarshal, &previousFeed)
+	if err != nil {
+		return nil, fmt.Errorf("renderFeed: could not read previous feed: %w", err)
+	}
+	entries := previousFeed.Entries
+
+	entries = slices.DeleteFunc(entries, func(e atomEntry) bool {
+		return e.ID == id
+	})
+
+	// entries published before they linked to their day are fixed up too
+	for i, curr := range entries {
+		if day, ok := entryDay(curr.ID); ok {
+			entries[i].Links = entryLinks(day)
+		}
+	}
+
+	entries = append(entries, atomEntry{
+		Title:   entryTitle(results, seattleToday),
+		ID:      id,
+		Updated: updated,
+		Links:   entryLinks(seattleToday),
+		Content: atomText{Type: "text", Body: entryContent(results)},
+	})
+
+	slices.SortStableFunc(entries, func(a, b atomEntry) int {
+		return entryTime(b).Compare(entryTime(a))
+	})
+	if len(entries) > MaxEntries {
+		entries = entries[:MaxEntries]
+	}
+
+	feed := atomFeed{
+		Title:   feedTitle,
+		ID:      site.URL,
+		Updated: entries[0].Updated,
+		Links: []atomLink{
+			{Href: site.URL, Rel: "alternate", Type: "text/html"},
+			{Href: site.URL + FeedKey, Rel: "self", Type: "application/atom+xml"},
+		},
+		Author:  &atomPerson{Name: authorName},
+		Entries: entries,
+	}
+
+	buf := bytes.NewBufferString(xml.Header)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	err = enc.Encode(feed)
+	if err != nil {
+		return nil, fmt.Errorf("renderFeed: could not render: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
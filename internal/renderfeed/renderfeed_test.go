@@ -0,0 +1,89 @@
+package renderfeed
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func TestRenderFeed(t *testing.T) {
+	day1 := time.Date(2026, time.November, 1, 3, 14, 0, 0, events.SeattleTimeZone)
+	day2 := day1.AddDate(0, 0, 1)
+
+	gameDay := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{ID: "espn:1", TeamName: "Seattle Kraken", Opponent: "Vancouver Canucks", Venue: "Climate Pledge Arena", LocalTime: "7:00 PM"},
+		},
+	}
+
+	first, err := RenderFeed(nil, gameDay, day1)
+	require.NoError(t, err)
+
+	second, err := RenderFeed(first, &events.EventResults{}, day2)
+	require.NoError(t, err)
+
+	// rendering the same day again should replace that day's entry
+	third, err := RenderFeed(second, &events.EventResults{}, day2)
+	require.NoError(t, err)
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(third, &feed))
+
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, "tag:isthereaseattlehomegametoday.com,2026-11-02:daily", feed.Entries[0].ID)
+	assert.Equal(t, "NO: nothing in Seattle on Monday Nov  2, 2026", feed.Entries[0].Title)
+	assert.Equal(t, "YES: 1 event in Seattle on Sunday Nov  1, 2026", feed.Entries[1].Title)
+	assert.Contains(t, feed.Entries[1].Content.Body, "* Seattle Kraken are playing against the Vancouver Canucks at Climate Pledge Arena. The game starts at 7:00 PM.")
+	assert.Equal(t, feed.Entries[0].Updated, feed.Updated)
+
+	// each entry links to its own day
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/archive/2026/11/02.html", feed.Entries[0].Links[0].Href)
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/archive/2026/11/01.html", feed.Entries[1].Links[0].Href)
+
+	// a feed we can't read isn't replaced with one that only has today in it
+	_, err = RenderFeed([]byte("<not a feed"), gameDay, day1)
+	assert.ErrorContains(t, err, "could not read previous feed")
+}
+
+func TestRenderFeedKeepsMaxEntries(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 3, 14, 0, 0, events.SeattleTimeZone)
+
+	var output []byte
+	var err error
+	for i := range MaxEntries + 5 {
+		output, err = RenderFeed(output, &events.EventResults{}, start.AddDate(0, 0, i))
+		require.NoError(t, err)
+	}
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(output, &feed))
+	require.Len(t, feed.Entries, MaxEntries)
+	assert.Equal(t, entryID(start.AddDate(0, 0, MaxEntries+4)), feed.Entries[0].ID)
+}
+
+func TestRenderFeed_FixesOldLinks(t *testing.T) {
+	previous := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Is there a Seattle home game today?</title>
+  <entry>
+    <title>NO: nothing in Seattle on Sunday Nov  1, 2026</title>
+    <id>tag:isthereaseattlehomegametoday.com,2026-11-01:daily</id>
+    <updated>2026-11-01T03:14:00-08:00</updated>
+    <link href="https://isthereaseattlehomegametoday.com/" rel="alternate" type="text/html"></link>
+    <content type="text">Nothing is scheduled today.</content>
+  </entry>
+</feed>`
+
+	output, err := RenderFeed([]byte(previous), &events.EventResults{}, time.Date(2026, time.November, 2, 3, 14, 0, 0, events.SeattleTimeZone))
+	require.NoError(t, err)
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(output, &feed))
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/archive/2026/11/01.html", feed.Entries[1].Links[0].Href)
+}
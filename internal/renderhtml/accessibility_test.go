@@ -0,0 +1,256 @@
+package renderhtml
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/i18n"
+)
+
+// pageClasses are the classes we use that come from Pico instead of our stylesheet. Pico's colors already meet WCAG AA
+// contrast, so these are fine to use anywhere.
+var pageClasses = []string{"container", "grid"}
+
+// minimumContrast is the WCAG AA contrast ratio for normal text
+const minimumContrast = 4.5
+
+func attr(n *html.Node, key string) (string, bool) {
+	for _, curr := range n.Attr {
+		if curr.Key == key {
+			return curr.Val, true
+		}
+	}
+	return "", false
+}
+
+func walk(n *html.Node, f func(n *html.Node)) {
+	f(n)
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		walk(child, f)
+	}
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	walk(n, func(curr *html.Node) {
+		if curr.Type == html.TextNode {
+			sb.WriteString(curr.Data)
+		}
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// relativeLuminance is from https://www.w3.org/TR/WCAG21/#dfn-relative-luminance
+func relativeLuminance(hex string) float64 {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	var channels [3]float64
+	for i := range channels {
+		v, _ := strconv.ParseUint(hex[i*2:i*2+2], 16, 8)
+		c := float64(v) / 255
+		if c <= 0.03928 {
+			channels[i] = c / 12.92
+		} else {
+			channels[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+
+	return 0.2126*channels[0] + 0.7152*channels[1] + 0.0722*channels[2]
+}
+
+func contrastRatio(a string, b string) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+var (
+	cssClassPattern = regexp.MustCompile(`\.([a-zA-Z][\w-]*)`)
+	cssColorPattern = regexp.MustCompile(`(?m)^\s*color:\s*([^;]+);`)
+	cssVarFallback  = regexp.MustCompile(`^var\(--pico-[\w-]+(?:,\s*(#[0-9a-fA-F]{3,6}))?\)$`)
+)
+
+// checkAccessibility parses a rendered page and checks the rules we care about
+func checkAccessibility(t *testing.T, page []byte) {
+	t.Helper()
+
+	doc, err := html.Parse(bytes.NewReader(page))
+	require.NoError(t, err)
+
+	definedClasses := map[string]bool{}
+	for _, curr := range pageClasses {
+		definedClasses[curr] = true
+	}
+	for _, curr := range cssClassPattern.FindAllStringSubmatch(cssString, -1) {
+		definedClasses[curr[1]] = true
+	}
+
+	var headingLevels []int
+	landmarks := map[string]int{}
+	var navs []*html.Node
+	var answer *html.Node
+
+	walk(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+
+		if lang, ok := attr(n, "lang"); ok {
+			assert.NotEmpty(t, lang, "%s has an empty lang", n.Data)
+		}
+
+		if class, ok := attr(n, "class"); ok {
+			for _, curr := range strings.Fields(class) {
+				assert.True(t, definedClasses[curr], "class %s isn't from Pico or our stylesheet, so we don't know its contrast", curr)
+			}
+		}
+
+		if id, _ := attr(n, "id"); id == "answer" {
+			answer = n
+		}
+
+		switch n.Data {
+		case "html":
+			lang, _ := attr(n, "lang")
+			assert.NotEmpty(t, lang, "page has no lang")
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			headingLevels = append(headingLevels, int(n.Data[1]-'0'))
+			assert.NotEmpty(t, textContent(n), "empty %s", n.Data)
+		case "img":
+			_, ok := attr(n, "alt")
+			assert.True(t, ok, "image has no alt text")
+		case "a":
+			label, _ := attr(n, "aria-label")
+			assert.True(t, textContent(n) != "" || label != "", "link has no text")
+		case "time":
+			datetime, ok := attr(n, "datetime")
+			require.True(t, ok, "time has no datetime")
+			_, rfcErr := time.Parse(time.RFC3339, datetime)
+			_, dateErr := time.Parse(time.DateOnly, datetime)
+			assert.True(t, rfcErr == nil || dateErr == nil, "time has unparseable datetime %s", datetime)
+		case "header", "main", "footer":
+			landmarks[n.Data]++
+		case "nav":
+			navs = append(navs, n)
+		case "ul":
+			for child := n.FirstChild; child != nil; child = child.NextSibling {
+				if child.Type == html.ElementNode {
+					assert.Equal(t, "li", child.Data, "list has a child that isn't a list item")
+				}
+			}
+		}
+	})
+
+	require.NotEmpty(t, headingLevels, "page has no headings")
+	assert.Equal(t, 1, headingLevels[0], "first heading isn't an h1")
+	for i := 1; i < len(headingLevels); i++ {
+		assert.LessOrEqual(t, headingLevels[i], headingLevels[i-1]+1, "heading levels skip from h%d to h%d", headingLevels[i-1], headingLevels[i])
+	}
+
+	assert.Equal(t, map[string]int{"header": 1, "main": 1, "footer": 1}, landmarks)
+	if len(navs) > 1 {
+		for _, curr := range navs {
+			label, _ := attr(curr, "aria-label")
+			assert.NotEmpty(t, label, "with more than one nav, each needs a label")
+		}
+	}
+
+	require.NotNil(t, answer, "page has no answer")
+	liveness, _ := attr(answer, "aria-live")
+	assert.Equal(t, "polite", liveness)
+}
+
+func TestRenderPage_Accessibility(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				TeamName:  "Seattle Mariners",
+				Opponent:  "Houston Astros",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				RawTime:   start.Unix(),
+				Links:     []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com/tickets"}},
+			},
+			{
+				Name:      "Some Band",
+				Venue:     "Climate Pledge Arena",
+				LocalTime: "TBA",
+				RawTime:   seattleToday.Unix(),
+				Status:    events.StatusPostponed,
+			},
+		},
+		TomorrowEvents: []*events.Event{
+			{
+				RawDescription: "Something special is happening",
+				RawTime:        start.AddDate(0, 0, 1).Unix(),
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		results *events.EventResults
+		opts    PageOptions
+	}{
+		"everything": {
+			results: results,
+			opts: PageOptions{
+				PreviousDayURL: "/archive/2026/05/01.html",
+				NextDayURL:     "/archive/2026/05/03.html",
+				VenueLinks:     []NavLink{{Name: "T-Mobile Park", URL: "/venue/t-mobile-park/index.html"}},
+				TeamLinks:      []NavLink{{Name: "Seattle Mariners", URL: "/team/mariners/index.html"}},
+				Translations:   i18n.Locales,
+			},
+		},
+		"nothing": {
+			results: &events.EventResults{},
+		},
+		"translated": {
+			results: results,
+			opts:    PageOptions{Locale: i18n.Locales[2], Translations: i18n.Locales},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			page, err := RenderPage(test.results, seattleToday, test.opts)
+			require.NoError(t, err)
+
+			checkAccessibility(t, page)
+		})
+	}
+}
+
+func TestStylesheetContrast(t *testing.T) {
+	colors := cssColorPattern.FindAllStringSubmatch(cssString, -1)
+	require.NotEmpty(t, colors)
+
+	for _, curr := range colors {
+		value := strings.TrimSpace(curr[1])
+
+		// text colors have to come from Pico so they follow the light and dark themes, and any fallback has to be
+		// readable on white
+		match := cssVarFallback.FindStringSubmatch(value)
+		require.NotNil(t, match, "color %s doesn't come from Pico", value)
+		if match[1] != "" {
+			assert.GreaterOrEqual(t, contrastRatio(match[1], "#fff"), minimumContrast, "fallback color %s isn't readable", match[1])
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package renderhtml
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/i18n"
+)
+
+// timePlaceholder stands in for a time in a translated message, so the rest of the message can be escaped before the
+// time is swapped out for a <time> element
+const timePlaceholder = "\uE000"
+
+// withTimeElement escapes message and replaces the placeholder in it with a <time> element that shows text and has
+// datetime as its machine-readable value
+func withTimeElement(message string, datetime string, text string) template.HTML {
+	element := fmt.Sprintf(`<time datetime="%s">%s</time>`, template.HTMLEscapeString(datetime), template.HTMLEscapeString(text))
+
+	//#nosec G203 -- everything other than the element we built was escaped
+	return template.HTML(strings.Replace(template.HTMLEscapeString(message), timePlaceholder, element, 1))
+}
+
+// cardLink is a link shown on an event card, with its label already translated
+type cardLink struct {
+	URL   string
+	Label string
+}
+
+// eventCard is what the event-card partial gets for each event. It has the same String, StatusLabel and Links as an
+// Event, but in the page's language.
+type eventCard struct {
+	*events.Event
+	locale *i18n.Locale
+}
+
+func (c eventCard) String() string {
+	return c.locale.Describe(c.Event)
+}
+
+// DateTime is the machine-readable start of the event. Events without a start time yet only get a date.
+func (c eventCard) DateTime() string {
+	if c.LocalTime == timeTBA {
+		return c.StartTime().Format(time.DateOnly)
+	}
+	return c.StartTime().Format(time.RFC3339)
+}
+
+// Start and End are the machine-readable start and end of the event for the clock script. They're empty if we don't know
+// when it starts or it isn't happening, since there's nothing to count down to.
+func (c eventCard) Start() string {
+	if c.LocalTime == timeTBA || !c.IsHappening() {
+		return ""
+	}
+	return c.StartTime().Format(time.RFC3339)
+}
+
+func (c eventCard) End() string {
+	if c.Start() == "" {
+		return ""
+	}
+	return c.EndTime().Format(time.RFC3339)
+}
+
+// hasClock is whether any of the cards has a start time for the clock script to count down to
+func hasClock(cards []eventCard) bool {
+	for _, curr := range cards {
+		if curr.Start() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Sentence is String with the start time marked up as a <time> element
+func (c eventCard) Sentence() template.HTML {
+	return withTimeElement(c.locale.DescribeAt(c.Event, timePlaceholder), c.DateTime(), c.locale.EventTime(c.Event))
+}
+
+func (c eventCard) StatusLabel() string {
+	return c.locale.StatusLabel(c.Event)
+}
+
+// Links shadows Event.Links so that the labels are translated
+func (c eventCard) Links() []cardLink {
+	links := make([]cardLink, len(c.Event.Links))
+	for i, curr := range c.Event.Links {
+		links[i] = cardLink{
+			URL:   curr.URL,
+			Label: c.locale.LinkLabel(curr),
+		}
+	}
+	return links
+}
+
+func newEventCards(x []*events.Event, locale *i18n.Locale) []eventCard {
+	collapsed := events.CollapseSessions(x)
+	cards := make([]eventCard, len(collapsed))
+	for i, curr := range collapsed {
+		cards[i] = eventCard{Event: curr, locale: locale}
+	}
+	return cards
+}
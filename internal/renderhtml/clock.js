@@ -0,0 +1,56 @@
+// The page is generated early in the morning, so on its own it can only say when things start. This turns the start
+// and end times on each event card in to "starts in 2 hours", "in progress" or "ended" for whoever is looking at it.
+// Without JavaScript, the cards still have their start times, so nothing is lost.
+(function () {
+    var labels = document.currentScript.dataset;
+    var lang = document.documentElement.lang;
+    var cards = document.querySelectorAll("[data-start][data-end]");
+    if (!cards.length || !window.Intl) {
+        return;
+    }
+
+    var relative = Intl.RelativeTimeFormat ? new Intl.RelativeTimeFormat(lang, {numeric: "auto"}) : null;
+
+    // people not in Seattle get the start time in their own time zone when they hover over it
+    if (Intl.DateTimeFormat().resolvedOptions().timeZone !== labels.timeZone) {
+        var local = new Intl.DateTimeFormat(lang, {hour: "numeric", minute: "2-digit", timeZoneName: "short"});
+        cards.forEach(function (card) {
+            card.querySelectorAll("time").forEach(function (time) {
+                time.title = local.format(new Date(time.dateTime));
+            });
+        });
+    }
+
+    function describe(start, end, now) {
+        if (now >= end) {
+            return labels.ended;
+        }
+        if (now >= start) {
+            return labels.inProgress;
+        }
+        if (!relative) {
+            return "";
+        }
+
+        var minutes = Math.ceil((start - now) / 60000);
+        var until = minutes < 60 ? relative.format(minutes, "minute") : relative.format(Math.round(minutes / 60), "hour");
+        return labels.startsIn.replace("%s", until);
+    }
+
+    function update() {
+        var now = Date.now();
+        cards.forEach(function (card) {
+            var clock = card.querySelector(".event-clock");
+            if (!clock) {
+                return;
+            }
+
+            var text = describe(Date.parse(card.dataset.start), Date.parse(card.dataset.end), now);
+            clock.textContent = text;
+            clock.hidden = !text;
+        });
+    }
+
+    update();
+    setInterval(update, 60 * 1000);
+})();
@@ -2,67 +2,195 @@ package renderhtml
 
 import (
 	"bytes"
-	_ "embed"
 	"fmt"
 	"html/template"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/lthummus/seattle-sports-today/internal/events"
-)
-
-//go:embed index.gohtml
-var templateString string
-
-//go:embed seattle-sports-today.css
-var cssString string
-
-var (
-	pageTemplate *template.Template
-	cssTemplate  template.CSS
+	"github.com/lthummus/seattle-sports-today/internal/i18n"
+	"github.com/lthummus/seattle-sports-today/internal/renderimage"
+	"github.com/lthummus/seattle-sports-today/internal/site"
 )
 
 func init() {
 	var err error
-	pageTemplate, err = template.New("").Parse(templateString)
+	defaultTheme, err = LoadTheme("")
 	if err != nil {
-		log.Fatal().Err(err).Msg("could not parse template")
+		log.Fatal().Err(err).Msg("could not load default theme")
 	}
+}
+
+// NavLink is a link to another page on the site
+type NavLink struct {
+	Name string
+	URL  string
+}
+
+// PageOptions controls the parts of the page that depend on where the page is being published
+type PageOptions struct {
+	// Title is the question the page answers. Defaults to asking about Seattle home games.
+	Title string
+
+	// URL is where the page is published, used as its canonical URL. Defaults to the main page in the page's locale.
+	URL string
+
+	// ImageKey is where the page's preview image is published. Defaults to the main page's.
+	ImageKey string
+
+	// PreviousDayURL and NextDayURL link to the pages for the day before and the day after. Either can be empty, in
+	// which case that link isn't shown.
+	PreviousDayURL string
+	NextDayURL     string
+
+	// Archive is set for pages that are kept around after their day is over. They don't get the clock script, since
+	// everything on them will have ended by the time anyone looks.
+	Archive bool
+
+	// VenueLinks and TeamLinks link to the pages for each venue and team
+	VenueLinks []NavLink
+	TeamLinks  []NavLink
+
+	// Theme replaces the default templates and CSS. Leave it nil to use the defaults.
+	Theme *Theme
 
-	// we embed the special, seattle sports today CSS directly in to the HTML file. Originally, it was served separately,
-	// but I wa tired of hand-maintaining the cache-busting hash and figured this was easier. It's less than 2 kB of CSS
-	// so no big deal
-	cssTemplate = template.CSS(cssString) //#nosec G203 -- entirely static
+	// Locale is the language the page is in. Defaults to English.
+	Locale *i18n.Locale
+
+	// Translations are the locales this page is also published in. They get hreflang links and a language switcher.
+	Translations []*i18n.Locale
+}
+
+// LanguageLink links to a copy of the page in another language
+type LanguageLink struct {
+	Lang string
+	Name string
+	URL  string
 }
 
 type templateParams struct {
-	Events            []*events.Event
-	Tomorrow          []*events.Event
+	L                 *i18n.Locale
+	HasGames          bool
+	Answer            string
+	Events            []eventCard
+	Tomorrow          []eventCard
 	GeneratedDate     string
+	GeneratedOn       template.HTML
 	FullGeneratedDate template.HTML
 	TomorrowHeading   string
 	Style             template.CSS
+	Title             string
+	PreviousDayURL    string
+	NextDayURL        string
+	VenueLinks        []NavLink
+	TeamLinks         []NavLink
+	StructuredData    template.JS
+	ClockScript       template.JS
+	TimeZone          string
+	Description       string
+	CanonicalURL      string
+	ImageURL          string
+	HrefLangs         []LanguageLink
+	LanguageLinks     []LanguageLink
 }
 
-func tomorrowHeader(gamesToday, gamesTomorrow bool) string {
+func tomorrowHeader(locale *i18n.Locale, gamesToday, gamesTomorrow bool) string {
 	if gamesToday && gamesTomorrow {
-		return "And there's more tomorrow...."
+		return locale.T("tomorrow.both")
 	} else if gamesToday {
 		// game today but not tomorrow
-		return "But nothing is scheduled tomorrow (yet?)...."
+		return locale.T("tomorrow.today_only")
 	} else if gamesTomorrow {
 		// game tomorrow but not today
-		return "But things pick up tomorrow...."
+		return locale.T("tomorrow.tomorrow_only")
 	} else {
 		// nothing today, nothing tomorrow
-		return "And it's all quiet tomorrow too..."
+		return locale.T("tomorrow.none")
 	}
 }
 
-func RenderPage(results *events.EventResults, seattleToday time.Time) ([]byte, error) {
-	generatedDateString := seattleToday.Format("Monday Jan _2, 2006")
-	buf := bytes.NewBuffer(nil)
+// pageDescription is a one line summary of today used in link previews
+func pageDescription(locale *i18n.Locale, results *events.EventResults, seattleToday time.Time) string {
+	var summaries []string
+	for _, curr := range events.CollapseSessions(results.TodayEvent) {
+		if curr.IsHappening() {
+			summaries = append(summaries, locale.Summarize(curr))
+		}
+	}
+
+	date := locale.FormatShortDate(seattleToday)
+	if len(summaries) == 0 {
+		return locale.T("description.nothing", date)
+	}
+	return fmt.Sprintf("%s: %s", date, strings.Join(summaries, "; "))
+}
+
+// languageLinks builds the links to each translation of the page. hrefLangs also has the x-default link that search
+// engines want, pointing at the English page.
+func languageLinks(translations []*i18n.Locale) (hrefLangs []LanguageLink, links []LanguageLink) {
+	if len(translations) == 0 {
+		return nil, nil
+	}
+
+	for _, curr := range translations {
+		links = append(links, LanguageLink{
+			Lang: curr.Code,
+			Name: curr.Name,
+			URL:  site.PageURL(curr.IndexKey()),
+		})
+	}
+
+	hrefLangs = append(hrefLangs, links...)
+	hrefLangs = append(hrefLangs, LanguageLink{
+		Lang: "x-default",
+		URL:  site.PageURL(i18n.Default.IndexKey()),
+	})
+
+	return hrefLangs, links
+}
+
+func newTemplateParams(results *events.EventResults, seattleToday time.Time, opts PageOptions) (*templateParams, error) {
+	locale := opts.Locale
+	if locale == nil {
+		locale = i18n.Default
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = locale.T("title")
+	}
+
+	answer := locale.T("no")
+	if events.AnyHappening(results.TodayEvent) {
+		answer = locale.T("yes")
+	}
+
+	canonicalURL := opts.URL
+	if canonicalURL == "" {
+		canonicalURL = site.PageURL(locale.IndexKey())
+	}
+
+	imageKey := opts.ImageKey
+	if imageKey == "" {
+		imageKey = renderimage.OGKey
+	}
+
+	hrefLangs, links := languageLinks(opts.Translations)
+
+	todayCards := newEventCards(results.TodayEvent, locale)
+
+	// the script only does anything for cards it can count down to
+	var clock template.JS
+	if !opts.Archive && hasClock(todayCards) {
+		clock = template.JS(clockScript) //#nosec G203 -- embedded at build time
+	}
+
+	ld, err := structuredData(results)
+	if err != nil {
+		return nil, err
+	}
 
 	// we have to do things this way because by default the Go HTML templating system will strip out comments. We can force it not
 	// to do that by passing this as a template.HTML already so the templating system will plonk it in there no questions asked.
@@ -70,14 +198,46 @@ func RenderPage(results *events.EventResults, seattleToday time.Time) ([]byte, e
 	//#nosec G203 -- We generate this with no involvement from the end user
 	generatedTimestamp := template.HTML(fmt.Sprintf("<!-- Generated at: %s -->", seattleToday.Format(time.RFC1123)))
 
-	err := pageTemplate.Execute(buf, &templateParams{
-		Events:            results.TodayEvent,
-		Tomorrow:          results.TomorrowEvents,
-		GeneratedDate:     generatedDateString,
+	return &templateParams{
+		L:                 locale,
+		HasGames:          events.AnyHappening(results.TodayEvent),
+		Answer:            answer,
+		Events:            todayCards,
+		Tomorrow:          newEventCards(results.TomorrowEvents, locale),
+		GeneratedDate:     locale.FormatDate(seattleToday),
+		GeneratedOn:       withTimeElement(locale.T("generated_on", timePlaceholder), seattleToday.Format(time.DateOnly), locale.FormatDate(seattleToday)),
 		FullGeneratedDate: generatedTimestamp,
-		TomorrowHeading:   tomorrowHeader(len(results.TodayEvent) > 0, len(results.TomorrowEvents) > 0),
-		Style:             cssTemplate,
-	})
+		TomorrowHeading:   tomorrowHeader(locale, events.AnyHappening(results.TodayEvent), events.AnyHappening(results.TomorrowEvents)),
+		Style:             opts.Theme.style,
+		Title:             title,
+		PreviousDayURL:    opts.PreviousDayURL,
+		NextDayURL:        opts.NextDayURL,
+		VenueLinks:        opts.VenueLinks,
+		TeamLinks:         opts.TeamLinks,
+		StructuredData:    ld,
+		ClockScript:       clock,
+		TimeZone:          events.SeattleTimeZone.String(),
+		Description:       pageDescription(locale, results, seattleToday),
+		CanonicalURL:      canonicalURL,
+		// link previews get cached aggressively, so make sure each day's image has its own URL
+		ImageURL:      fmt.Sprintf("%s%s?d=%s", site.URL, imageKey, seattleToday.Format("20060102")),
+		HrefLangs:     hrefLangs,
+		LanguageLinks: links,
+	}, nil
+}
+
+func RenderPage(results *events.EventResults, seattleToday time.Time, opts PageOptions) ([]byte, error) {
+	if opts.Theme == nil {
+		opts.Theme = defaultTheme
+	}
+
+	params, err := newTemplateParams(results, seattleToday, opts)
+	if err != nil {
+		return nil, fmt.Errorf("renderPage: %w", err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = opts.Theme.execute(buf, params)
 	if err != nil {
 		return nil, fmt.Errorf("renderPage: could not render: %w", err)
 	}
@@ -0,0 +1,145 @@
+package renderhtml
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+	"github.com/lthummus/seattle-sports-today/internal/i18n"
+)
+
+func TestRenderPage_PreviewTags(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{ShortDescription: "Some Band is at Climate Pledge Arena"},
+			{ShortDescription: "Another Band is at WAMU Theater", Status: events.StatusCancelled},
+		},
+	}
+
+	page, err := RenderPage(results, seattleToday, PageOptions{})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(page), `<meta property="og:image" content="https://isthereaseattlehomegametoday.com/og.png?d=20260502">`)
+	assert.Contains(t, string(page), `<meta property="og:title" content="Is there a Seattle home game today? YES">`)
+	assert.Contains(t, string(page), `<meta property="og:description" content="Saturday, May 2: Some Band is at Climate Pledge Arena">`)
+	assert.Contains(t, string(page), `<meta name="twitter:card" content="summary_large_image">`)
+	assert.Contains(t, string(page), `<meta name="description" content="Saturday, May 2: Some Band is at Climate Pledge Arena">`)
+	assert.Contains(t, string(page), `<link rel="canonical" href="https://isthereaseattlehomegametoday.com/">`)
+	assert.Contains(t, string(page), `<meta property="og:url" content="https://isthereaseattlehomegametoday.com/">`)
+
+	page, err = RenderPage(&events.EventResults{}, seattleToday, PageOptions{URL: "https://isthereaseattlehomegametoday.com/venue/lumen-field/index.html", ImageKey: "venue/lumen-field/og.png"})
+	require.NoError(t, err)
+	assert.Contains(t, string(page), `<meta property="og:image" content="https://isthereaseattlehomegametoday.com/venue/lumen-field/og.png?d=20260502">`)
+	assert.Contains(t, string(page), `<meta property="og:title" content="Is there a Seattle home game today? NO">`)
+	assert.Contains(t, string(page), `<meta property="og:description" content="Nothing is happening in Seattle on Saturday, May 2.">`)
+	assert.Contains(t, string(page), `<link rel="canonical" href="https://isthereaseattlehomegametoday.com/venue/lumen-field/index.html">`)
+}
+
+func TestRenderPage_Locale(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				TeamName:  "Seattle Mariners",
+				Opponent:  "Houston Astros",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				RawTime:   start.Unix(),
+				Status:    events.StatusRescheduled,
+				Links:     []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com/tickets"}},
+			},
+		},
+	}
+
+	page, err := RenderPage(results, seattleToday, PageOptions{Locale: i18n.Locales[1], Translations: i18n.Locales})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(page), `<html lang="es">`)
+	assert.Contains(t, string(page), `<link rel="canonical" href="https://isthereaseattlehomegametoday.com/es/index.html">`)
+	assert.Contains(t, string(page), `<title>¿Hay un partido en casa en Seattle hoy?</title>`)
+	assert.Contains(t, string(page), `>SÍ</h1>`)
+	assert.Contains(t, string(page), `<mark class="status">REPROGRAMADO</mark> Seattle Mariners juegan contra Houston Astros en T-Mobile Park. El partido empieza a las <time datetime="2026-05-02T19:10:00-07:00">19:10</time>.`)
+	assert.Contains(t, string(page), `>Boletos</a>`)
+	assert.Contains(t, string(page), `Generado el <time datetime="2026-05-02">sábado 2 de mayo de 2026</time>`)
+
+	assert.Contains(t, string(page), `<link rel="alternate" hreflang="en" href="https://isthereaseattlehomegametoday.com/">`)
+	assert.Contains(t, string(page), `<link rel="alternate" hreflang="zh-Hans" href="https://isthereaseattlehomegametoday.com/zh/index.html">`)
+	assert.Contains(t, string(page), `<link rel="alternate" hreflang="x-default" href="https://isthereaseattlehomegametoday.com/">`)
+	assert.Contains(t, string(page), `hreflang="vi" lang="vi">Tiếng Việt</a>`)
+	assert.Contains(t, string(page), `<link rel="alternate" type="application/atom+xml" title="¿Hay un partido en casa en Seattle hoy?" href="/feed.xml">`)
+	assert.Contains(t, string(page), `<link rel="alternate" type="text/calendar" title="Calendario de partidos en casa en Seattle" href="/todays_events.ics">`)
+	assert.Contains(t, string(page), `<meta property="og:description" content="sábado 2 de mayo: Seattle Mariners juegan contra Houston Astros en T-Mobile Park">`)
+
+	// pages that aren't translated don't link to translations
+	page, err = RenderPage(results, seattleToday, PageOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(page), "hreflang")
+	assert.Contains(t, string(page), `<mark class="status">RESCHEDULED</mark> Seattle Mariners are playing against the Houston Astros at T-Mobile Park. The game starts at <time datetime="2026-05-02T19:10:00-07:00">7:10 PM</time>.`)
+}
+
+func TestRenderPage_Clock(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				TeamName:  "Seattle Mariners",
+				Opponent:  "Houston Astros",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				RawTime:   start.Unix(),
+			},
+			{
+				Name:      "Some Band",
+				Venue:     "Climate Pledge Arena",
+				LocalTime: "TBA",
+				RawTime:   seattleToday.Unix(),
+			},
+			{
+				TeamName:  "Seattle Sounders FC",
+				Opponent:  "Portland Timbers",
+				Venue:     "Lumen Field",
+				LocalTime: "1:00 PM",
+				RawTime:   time.Date(2026, time.May, 2, 13, 0, 0, 0, events.SeattleTimeZone).Unix(),
+				Status:    events.StatusPostponed,
+			},
+		},
+	}
+
+	page, err := RenderPage(results, seattleToday, PageOptions{Locale: i18n.Locales[1]})
+	require.NoError(t, err)
+
+	// only the game with a known start that is still happening gets a clock
+	assert.Equal(t, 1, strings.Count(string(page), "data-start="))
+	assert.Equal(t, 1, strings.Count(string(page), `<p class="event-clock" hidden></p>`))
+	assert.Contains(t, string(page), `<div data-start="2026-05-02T19:10:00-07:00" data-end="2026-05-02T22:10:00-07:00">`)
+
+	// the start time is still in the text for anyone without JavaScript
+	assert.Contains(t, string(page), `<time datetime="2026-05-02T19:10:00-07:00">19:10</time>`)
+
+	assert.Contains(t, string(page), `<script data-starts-in="Empieza %s" data-in-progress="En curso" data-ended="Terminado" data-time-zone="America/Los_Angeles">`)
+	assert.Contains(t, string(page), clockScript)
+
+	// archived days are over, so there's nothing to count down to
+	page, err = RenderPage(results, seattleToday, PageOptions{Archive: true})
+	require.NoError(t, err)
+	assert.NotContains(t, string(page), "<script data-starts-in")
+
+	// neither is there with nothing today that has a start time, even if tomorrow does
+	page, err = RenderPage(&events.EventResults{TodayEvent: results.TodayEvent[1:], TomorrowEvents: results.TodayEvent[:1]}, seattleToday, PageOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(page), "<script data-starts-in")
+
+	page, err = RenderPage(&events.EventResults{}, seattleToday, PageOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(page), "<script data-starts-in")
+}
@@ -0,0 +1,192 @@
+package renderhtml
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+// This file builds the schema.org JSON-LD that goes in the head of the page so search engines (and anything else that
+// reads structured data) can understand the events without scraping the cards
+
+const (
+	schemaOrgContext = "https://schema.org"
+
+	typeSportsEvent = "SportsEvent"
+	typeMusicEvent  = "MusicEvent"
+	typeEvent       = "Event"
+
+	timeTBA = "TBA"
+
+	// unknownVenueName is the location of events that don't have a venue. Search engines require one, and everything
+	// we list is in Seattle.
+	unknownVenueName = "Seattle, WA"
+)
+
+type ldPostalAddress struct {
+	Type            string `json:"@type"`
+	StreetAddress   string `json:"streetAddress"`
+	AddressLocality string `json:"addressLocality"`
+	AddressRegion   string `json:"addressRegion"`
+	PostalCode      string `json:"postalCode"`
+	AddressCountry  string `json:"addressCountry"`
+}
+
+type ldPlace struct {
+	Type    string           `json:"@type"`
+	Name    string           `json:"name"`
+	Address *ldPostalAddress `json:"address,omitempty"`
+}
+
+type ldSportsTeam struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type ldOffer struct {
+	Type string `json:"@type"`
+	URL  string `json:"url"`
+}
+
+type ldEvent struct {
+	Type                string         `json:"@type"`
+	Name                string         `json:"name"`
+	Description         string         `json:"description"`
+	StartDate           string         `json:"startDate"`
+	EndDate             string         `json:"endDate,omitempty"`
+	EventStatus         string         `json:"eventStatus"`
+	EventAttendanceMode string         `json:"eventAttendanceMode"`
+	Location            ldPlace        `json:"location"`
+	HomeTeam            *ldSportsTeam  `json:"homeTeam,omitempty"`
+	AwayTeam            *ldSportsTeam  `json:"awayTeam,omitempty"`
+	Competitor          []ldSportsTeam `json:"competitor,omitempty"`
+	Image               string         `json:"image,omitempty"`
+	URL                 string         `json:"url,omitempty"`
+	Offers              *ldOffer       `json:"offers,omitempty"`
+}
+
+type ldGraph struct {
+	Context string    `json:"@context"`
+	Graph   []ldEvent `json:"@graph"`
+}
+
+func ldEventType(e *events.Event) string {
+	switch e.Category {
+	case events.CategorySports:
+		return typeSportsEvent
+	case events.CategoryMusic:
+		return typeMusicEvent
+	default:
+		return typeEvent
+	}
+}
+
+func ldEventStatus(e *events.Event) string {
+	switch e.Status {
+	case events.StatusPostponed:
+		return "https://schema.org/EventPostponed"
+	case events.StatusRescheduled:
+		return "https://schema.org/EventRescheduled"
+	case events.StatusCancelled:
+		return "https://schema.org/EventCancelled"
+	default:
+		return "https://schema.org/EventScheduled"
+	}
+}
+
+func ldLocation(e *events.Event) ldPlace {
+	place := ldPlace{
+		Type: "Place",
+		Name: e.Venue,
+	}
+	if place.Name == "" {
+		place.Name = unknownVenueName
+	}
+
+	if venue := events.LookupVenue(e.Venue); venue != nil {
+		place.Name = venue.Name
+		place.Address = &ldPostalAddress{
+			Type:            "PostalAddress",
+			StreetAddress:   venue.Address.Street,
+			AddressLocality: venue.Address.City,
+			AddressRegion:   venue.Address.Region,
+			PostalCode:      venue.Address.PostalCode,
+			AddressCountry:  venue.Address.Country,
+		}
+	}
+
+	return place
+}
+
+func newLDEvent(e *events.Event) ldEvent {
+	ld := ldEvent{
+		Type:                ldEventType(e),
+		Name:                e.CalendarSummary(),
+		Description:         e.String(),
+		EventStatus:         ldEventStatus(e),
+		EventAttendanceMode: "https://schema.org/OfflineEventAttendanceMode",
+		Location:            ldLocation(e),
+		Image:               e.ImageURL,
+	}
+
+	if e.LocalTime == timeTBA {
+		// we made up the time for sorting, so only claim to know the date
+		ld.StartDate = e.StartTime().Format(time.DateOnly)
+	} else {
+		ld.StartDate = e.StartTime().Format(time.RFC3339)
+		ld.EndDate = e.EndTime().Format(time.RFC3339)
+	}
+
+	if e.TeamName != "" && e.Opponent != "" {
+		seattle := ldSportsTeam{Type: "SportsTeam", Name: e.TeamName}
+		opponent := ldSportsTeam{Type: "SportsTeam", Name: e.Opponent}
+		ld.Competitor = []ldSportsTeam{seattle, opponent}
+
+		// only claim a home team when the source told us which side we're on. Neutral site games don't have one.
+		switch e.HomeAway {
+		case events.HomeAwayHome:
+			ld.HomeTeam, ld.AwayTeam = &seattle, &opponent
+		case events.HomeAwayAway:
+			ld.HomeTeam, ld.AwayTeam = &opponent, &seattle
+		}
+	}
+
+	if link := e.PrimaryLink(); link != nil {
+		ld.URL = link.URL
+	}
+	for _, curr := range e.Links {
+		if curr.Kind == events.LinkKindTickets {
+			ld.Offers = &ldOffer{Type: "Offer", URL: curr.URL}
+			break
+		}
+	}
+
+	return ld
+}
+
+// structuredData renders the JSON-LD for every event on the page. Each session of a doubleheader is its own event here,
+// even though they share a card on the page.
+func structuredData(results *events.EventResults) (template.JS, error) {
+	graph := ldGraph{
+		Context: schemaOrgContext,
+		Graph:   []ldEvent{},
+	}
+
+	for _, curr := range results.TodayEvent {
+		graph.Graph = append(graph.Graph, newLDEvent(curr))
+	}
+	for _, curr := range results.TomorrowEvents {
+		graph.Graph = append(graph.Graph, newLDEvent(curr))
+	}
+
+	// json.Marshal escapes <, > and & so nothing in here can close the script tag
+	payload, err := json.Marshal(graph)
+	if err != nil {
+		return "", fmt.Errorf("structuredData: could not render: %w", err)
+	}
+
+	return template.JS(payload), nil //#nosec G203 -- escaped by json.Marshal
+}
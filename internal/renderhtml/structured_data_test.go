@@ -0,0 +1,178 @@
+package renderhtml
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+var ldScriptPattern = regexp.MustCompile(`(?s)<script type="application/ld\+json">(.*?)</script>`)
+
+func extractStructuredData(t *testing.T, page []byte) []map[string]any {
+	t.Helper()
+
+	match := ldScriptPattern.FindSubmatch(page)
+	require.NotNil(t, match, "page has no JSON-LD")
+
+	var graph map[string]any
+	require.NoError(t, json.Unmarshal(match[1], &graph))
+	assert.Equal(t, "https://schema.org", graph["@context"])
+
+	var ldEvents []map[string]any
+	for _, curr := range graph["@graph"].([]any) {
+		ldEvents = append(ldEvents, curr.(map[string]any))
+	}
+	return ldEvents
+}
+
+// validateLDEvent checks the properties that search engines require on an Event
+func validateLDEvent(t *testing.T, ld map[string]any) {
+	t.Helper()
+
+	assert.Contains(t, []any{"SportsEvent", "MusicEvent", "Event"}, ld["@type"])
+	assert.NotEmpty(t, ld["name"])
+	assert.Regexp(t, `^https://schema.org/Event(Scheduled|Postponed|Rescheduled|Cancelled)$`, ld["eventStatus"])
+
+	startDate := ld["startDate"].(string)
+	if _, err := time.Parse(time.DateOnly, startDate); err != nil {
+		_, err = time.Parse(time.RFC3339, startDate)
+		assert.NoError(t, err, "startDate must be a date or have an offset")
+	}
+
+	location := ld["location"].(map[string]any)
+	assert.Equal(t, "Place", location["@type"])
+	assert.NotEmpty(t, location["name"])
+}
+
+func TestStructuredData(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				ID:        "espn:1234",
+				TeamName:  "Seattle Mariners",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				Opponent:  "Houston Astros",
+				RawTime:   start.Unix(),
+				Category:  events.CategorySports,
+				HomeAway:  events.HomeAwayHome,
+				Links:     []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com/tickets?a=1&b=2"}},
+			},
+			{
+				ID:               "ticketmaster:abcd",
+				Venue:            "Climate Pledge Arena",
+				ShortDescription: "Some Band </script> is at Climate Pledge Arena",
+				RawTime:          start.Unix(),
+				Category:         events.CategoryMusic,
+				Status:           events.StatusCancelled,
+			},
+		},
+		TomorrowEvents: []*events.Event{
+			{
+				ID:               "special:2026-05-03-something",
+				Venue:            "Somewhere Else",
+				ShortDescription: "Something is happening",
+				LocalTime:        "TBA",
+				RawTime:          start.AddDate(0, 0, 1).Unix(),
+			},
+			{
+				ID:               "special:2026-05-03-parade",
+				ShortDescription: "There is a parade",
+				LocalTime:        "11:00 AM",
+				RawTime:          start.AddDate(0, 0, 1).Unix(),
+			},
+		},
+	}
+
+	page, err := RenderPage(results, seattleToday, PageOptions{})
+	require.NoError(t, err)
+
+	ldEvents := extractStructuredData(t, page)
+	require.Len(t, ldEvents, 4)
+	for _, curr := range ldEvents {
+		validateLDEvent(t, curr)
+	}
+
+	game := ldEvents[0]
+	assert.Equal(t, "SportsEvent", game["@type"])
+	assert.Equal(t, "2026-05-02T19:10:00-07:00", game["startDate"])
+	assert.Equal(t, "2026-05-02T22:10:00-07:00", game["endDate"])
+	assert.Equal(t, "https://example.com/tickets?a=1&b=2", game["url"])
+	assert.Equal(t, "Seattle Mariners", game["homeTeam"].(map[string]any)["name"])
+	assert.Equal(t, "Houston Astros", game["awayTeam"].(map[string]any)["name"])
+	assert.Len(t, game["competitor"], 2)
+
+	address := game["location"].(map[string]any)["address"].(map[string]any)
+	assert.Equal(t, "PostalAddress", address["@type"])
+	assert.Equal(t, "1250 1st Ave S", address["streetAddress"])
+	assert.Equal(t, "Seattle", address["addressLocality"])
+
+	concert := ldEvents[1]
+	assert.Equal(t, "MusicEvent", concert["@type"])
+	assert.Equal(t, "https://schema.org/EventCancelled", concert["eventStatus"])
+	assert.NotContains(t, concert, "homeTeam")
+
+	other := ldEvents[2]
+	assert.Equal(t, "Event", other["@type"])
+	assert.Equal(t, "2026-05-03", other["startDate"])
+	assert.NotContains(t, other, "endDate")
+	assert.NotContains(t, other["location"], "address")
+
+	// search engines need a location, so events without a venue are just in Seattle
+	parade := ldEvents[3]
+	assert.Equal(t, "Seattle, WA", parade["location"].(map[string]any)["name"])
+}
+
+func TestStructuredData_HomeAway(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+
+	game := func(homeAway events.HomeAway) *events.Event {
+		return &events.Event{
+			TeamName:  "Washington Huskies",
+			Opponent:  "Oregon Ducks",
+			Venue:     "Climate Pledge Arena",
+			LocalTime: "7:10 PM",
+			RawTime:   start.Unix(),
+			Category:  events.CategorySports,
+			HomeAway:  homeAway,
+		}
+	}
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{game(events.HomeAwayAway), game(events.HomeAwayNeutral), game("")},
+	}
+
+	page, err := RenderPage(results, seattleToday, PageOptions{})
+	require.NoError(t, err)
+
+	ldEvents := extractStructuredData(t, page)
+	require.Len(t, ldEvents, 3)
+
+	away := ldEvents[0]
+	assert.Equal(t, "Oregon Ducks", away["homeTeam"].(map[string]any)["name"])
+	assert.Equal(t, "Washington Huskies", away["awayTeam"].(map[string]any)["name"])
+
+	// without a home team, both sides are only competitors
+	for _, curr := range ldEvents[1:] {
+		assert.NotContains(t, curr, "homeTeam")
+		assert.NotContains(t, curr, "awayTeam")
+		assert.Len(t, curr["competitor"], 2)
+	}
+}
+
+func TestStructuredData_NoEvents(t *testing.T) {
+	page, err := RenderPage(&events.EventResults{}, time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone), PageOptions{})
+	require.NoError(t, err)
+
+	assert.Empty(t, extractStructuredData(t, page))
+}
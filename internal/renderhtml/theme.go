@@ -0,0 +1,165 @@
+package renderhtml
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+const (
+	// EnvVarThemeDir points at a directory of templates and CSS that replace the embedded ones
+	EnvVarThemeDir = "THEME_DIR"
+
+	pageTemplateFile = "index.gohtml"
+	themeCSSFile     = "style.css"
+)
+
+// themeTemplateFiles are the templates that make up the page. A theme directory can contain any of these, and any it
+// doesn't have come from the embedded defaults. The partials are:
+//
+//   - header.gohtml defines "header", the big YES or NO at the top of the page
+//   - event_card.gohtml defines "event-card", which is rendered once for every event
+//   - footer.gohtml defines "footer"
+var themeTemplateFiles = []string{pageTemplateFile, "header.gohtml", "event_card.gohtml", "footer.gohtml"}
+
+//go:embed index.gohtml header.gohtml event_card.gohtml footer.gohtml
+var embeddedTemplates embed.FS
+
+//go:embed seattle-sports-today.css
+var cssString string
+
+// clockScript updates each event card with whether it has started yet. It isn't part of the theme since it depends on
+// the data-start and data-end attributes more than on how the page looks.
+//
+//go:embed clock.js
+var clockScript string
+
+// Theme is the set of templates and CSS used to render the page
+type Theme struct {
+	page  *template.Template
+	style template.CSS
+}
+
+var defaultTheme *Theme
+
+// readThemeFile reads the named file from the theme directory, falling back to the embedded default if the directory
+// doesn't have it. dir may be empty, in which case the default is always used.
+func readThemeFile(dir string, name string, fallback func() ([]byte, error)) ([]byte, error) {
+	if dir != "" {
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return contents, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return fallback()
+}
+
+// LoadTheme loads the theme in dir on top of the embedded defaults and makes sure it can render a page. An empty dir
+// gives the default theme. This is meant to be called at startup so a broken theme is caught before we try to publish
+// anything with it.
+func LoadTheme(dir string) (*Theme, error) {
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("renderhtml: LoadTheme: could not read theme directory: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("renderhtml: LoadTheme: %s is not a directory", dir)
+		}
+	}
+
+	page := template.New("")
+	for _, curr := range themeTemplateFiles {
+		contents, err := readThemeFile(dir, curr, func() ([]byte, error) {
+			return embeddedTemplates.ReadFile(curr)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("renderhtml: LoadTheme: could not read %s: %w", curr, err)
+		}
+
+		_, err = page.New(curr).Parse(string(contents))
+		if err != nil {
+			return nil, fmt.Errorf("renderhtml: LoadTheme: could not parse %s: %w", curr, err)
+		}
+	}
+
+	css, err := readThemeFile(dir, themeCSSFile, func() ([]byte, error) {
+		return []byte(cssString), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("renderhtml: LoadTheme: could not read %s: %w", themeCSSFile, err)
+	}
+
+	theme := &Theme{
+		page: page,
+		// we embed the CSS directly in to the HTML file. Originally, it was served separately, but I wa tired of
+		// hand-maintaining the cache-busting hash and figured this was easier. It's less than 2 kB of CSS so no big deal
+		style: template.CSS(css), //#nosec G203 -- comes from us or whoever is running the site, never the end user
+	}
+
+	err = theme.validate()
+	if err != nil {
+		return nil, fmt.Errorf("renderhtml: LoadTheme: %w", err)
+	}
+
+	return theme, nil
+}
+
+func (t *Theme) execute(w io.Writer, params *templateParams) error {
+	return t.page.ExecuteTemplate(w, pageTemplateFile, params)
+}
+
+// validate renders a page with some made up events, so things like a partial referring to a field that doesn't exist
+// blow up now instead of the next time there's a game
+func (t *Theme) validate() error {
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+	sample := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				TeamName:  "Seattle Mariners",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				Opponent:  "Houston Astros",
+				RawTime:   start.Unix(),
+				Links:     []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com"}},
+			},
+		},
+		TomorrowEvents: []*events.Event{
+			{
+				ShortDescription: "Something is at Climate Pledge Arena",
+				RawTime:          start.AddDate(0, 0, 1).Unix(),
+				Status:           events.StatusPostponed,
+			},
+		},
+	}
+
+	params, err := newTemplateParams(sample, start, PageOptions{
+		PreviousDayURL: "/yesterday",
+		NextDayURL:     "/tomorrow",
+		VenueLinks:     []NavLink{{Name: "Venue", URL: "/venue"}},
+		TeamLinks:      []NavLink{{Name: "Team", URL: "/team"}},
+		Theme:          t,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = t.execute(io.Discard, params)
+	if err != nil {
+		return fmt.Errorf("theme could not render a page: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package renderhtml
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func writeThemeFile(t *testing.T, dir string, name string, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o600))
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "header.gohtml", `{{ define "header" }}<header><h1>Tacoma says {{ if .HasGames }}YES{{ else }}NO{{ end }}</h1></header>{{ end }}`)
+	writeThemeFile(t, dir, "style.css", "body { color: hotpink; }")
+
+	theme, err := LoadTheme(dir)
+	require.NoError(t, err)
+
+	page, err := RenderPage(&events.EventResults{}, time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone), PageOptions{Theme: theme})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(page), "<h1>Tacoma says NO</h1>")
+	assert.Contains(t, string(page), "body { color: hotpink; }")
+	// everything not in the theme directory comes from the defaults
+	assert.Contains(t, string(page), `<footer class="container site-footer">`)
+	assert.NotContains(t, string(page), `id="answer"`)
+}
+
+func TestLoadTheme_Invalid(t *testing.T) {
+	_, err := LoadTheme(filepath.Join(t.TempDir(), "does-not-exist"))
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	writeThemeFile(t, dir, "event_card.gohtml", `{{ define "event-card" }}<p>{{ .NoSuchField }}</p>{{ end }}`)
+	_, err = LoadTheme(dir)
+	assert.ErrorContains(t, err, "NoSuchField")
+
+	dir = t.TempDir()
+	writeThemeFile(t, dir, "footer.gohtml", `{{ define "footer" }}{{ if }}{{ end }}`)
+	_, err = LoadTheme(dir)
+	assert.ErrorContains(t, err, "footer.gohtml")
+}
@@ -0,0 +1,175 @@
+package renderics
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+const (
+	productID = "-//isthereaseattlehomegametoday.com//Seattle Sports Today//EN"
+	uidDomain = "isthereaseattlehomegametoday.com"
+
+	timeZoneID = "America/Los_Angeles"
+
+	dateFormat          = "20060102"
+	localDateTimeFormat = "20060102T150405"
+	utcDateTimeFormat   = "20060102T150405Z"
+
+	// RFC 5545 says lines "SHOULD NOT" be longer than 75 octets
+	maxLineLength = 75
+
+	timeTBA = "TBA"
+)
+
+// vtimezone describes Pacific time for calendar apps that don't know about it. These are the DST rules that have been
+// in effect since 2007, which is good enough for a feed that only ever has the next couple of days in it.
+var vtimezone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:" + timeZoneID,
+	"X-LIC-LOCATION:" + timeZoneID,
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:-0800",
+	"TZOFFSETTO:-0700",
+	"TZNAME:PDT",
+	"DTSTART:19700308T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:-0700",
+	"TZOFFSETTO:-0800",
+	"TZNAME:PST",
+	"DTSTART:19701101T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
+
+type calendarWriter struct {
+	buf bytes.Buffer
+}
+
+// writeLine writes a content line, folding it if it is too long. Continuation lines start with a space, which counts
+// toward their length. Folding is done on octets, but we're careful not to split a multibyte character in half.
+func (w *calendarWriter) writeLine(line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.buf.WriteString(line[:cut])
+		w.buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(line)
+	w.buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func (w *calendarWriter) writeProperty(name string, value string) {
+	w.writeLine(fmt.Sprintf("%s:%s", name, value))
+}
+
+func (w *calendarWriter) writeText(name string, value string) {
+	w.writeProperty(name, escapeText(value))
+}
+
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// UID builds the iCalendar UID for the event. It is based on the event's source ID rather than its key, since the key
+// includes the start time. That way the UID stays the same from run to run, even if the game is rescheduled, and
+// calendar apps update the event in place rather than adding a copy.
+func UID(e *events.Event) string {
+	return fmt.Sprintf("%s@%s", strings.ReplaceAll(e.ID, ":", "-"), uidDomain)
+}
+
+func icsStatus(e *events.Event) string {
+	switch e.Status {
+	case events.StatusCancelled:
+		return "CANCELLED"
+	case events.StatusPostponed:
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+func (w *calendarWriter) writeEvent(e *events.Event, stamp time.Time) {
+	w.writeLine("BEGIN:VEVENT")
+	w.writeProperty("UID", UID(e))
+	w.writeProperty("DTSTAMP", stamp.UTC().Format(utcDateTimeFormat))
+	if e.LocalTime == timeTBA {
+		// the time is made up for sorting, so it's an all day event instead
+		start := e.StartTime()
+		w.writeProperty("DTSTART;VALUE=DATE", start.Format(dateFormat))
+		w.writeProperty("DTEND;VALUE=DATE", start.AddDate(0, 0, 1).Format(dateFormat))
+	} else {
+		w.writeProperty("DTSTART;TZID="+timeZoneID, e.StartTime().Format(localDateTimeFormat))
+		w.writeProperty("DTEND;TZID="+timeZoneID, e.EndTime().Format(localDateTimeFormat))
+	}
+	w.writeText("SUMMARY", e.CalendarTitle())
+	w.writeText("DESCRIPTION", e.CalendarDescription())
+	if e.Venue != "" {
+		w.writeText("LOCATION", e.Venue)
+	}
+	if link := e.PrimaryLink(); link != nil {
+		w.writeProperty("URL", link.URL)
+	}
+	w.writeProperty("STATUS", icsStatus(e))
+	w.writeLine("END:VEVENT")
+}
+
+// Render renders today's and tomorrow's events in results in to an iCalendar feed named calendarName
+func Render(calendarName string, results *events.EventResults, generated time.Time) []byte {
+	w := &calendarWriter{}
+
+	w.writeLine("BEGIN:VCALENDAR")
+	w.writeProperty("VERSION", "2.0")
+	w.writeProperty("PRODID", productID)
+	w.writeProperty("CALSCALE", "GREGORIAN")
+	w.writeProperty("METHOD", "PUBLISH")
+	w.writeText("X-WR-CALNAME", calendarName)
+	w.writeProperty("X-WR-TIMEZONE", timeZoneID)
+
+	for _, curr := range vtimezone {
+		w.writeLine(curr)
+	}
+
+	for _, curr := range results.TodayEvent {
+		w.writeEvent(curr, generated)
+	}
+	for _, curr := range results.TomorrowEvents {
+		w.writeEvent(curr, generated)
+	}
+
+	w.writeLine("END:VCALENDAR")
+
+	return w.buf.Bytes()
+}
+
+// RenderTeamICS renders a team's feed. results should already be filtered down to the team's home games.
+func RenderTeamICS(teamName string, results *events.EventResults, seattleToday time.Time) []byte {
+	return Render(fmt.Sprintf("%s home games", teamName), results, seattleToday)
+}
+
+// RenderICS renders today's and tomorrow's events in to an iCalendar feed. The generated date is used as the DTSTAMP
+// so that rendering the same day twice gives the same output.
+func RenderICS(results *events.EventResults, seattleToday time.Time) []byte {
+	return Render("Is there a Seattle home game today?", results, seattleToday)
+}
@@ -0,0 +1,108 @@
+package renderics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func TestRenderICS(t *testing.T) {
+	today := time.Date(2026, time.July, 4, 0, 0, 0, 0, events.SeattleTimeZone)
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				ID:        "espn:1",
+				TeamName:  "Seattle Mariners",
+				Opponent:  "Houston Astros",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				RawTime:   time.Date(2026, time.July, 4, 19, 10, 0, 0, events.SeattleTimeZone).Unix(),
+				Links:     []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com/tickets"}},
+			},
+		},
+		TomorrowEvents: []*events.Event{
+			{
+				ID:               "ticketmaster:2",
+				Venue:            "Climate Pledge Arena",
+				ShortDescription: "Some Band, With Friends; Live is at Climate Pledge Arena and they are going to play for a very long time",
+				RawDescription:   "Some Band, With Friends; Live is at Climate Pledge Arena. It starts at 8:00 PM",
+				RawTime:          time.Date(2026, time.July, 5, 20, 0, 0, 0, events.SeattleTimeZone).Unix(),
+				Status:           events.StatusCancelled,
+			},
+		},
+	}
+
+	output := RenderICS(results, today)
+
+	ics := string(output)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength, "line too long: %s", line)
+	}
+
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, "UID:espn-1@isthereaseattlehomegametoday.com\r\n")
+	assert.Contains(t, unfolded, "DTSTART;TZID=America/Los_Angeles:20260704T191000\r\n")
+	assert.Contains(t, unfolded, "DTEND;TZID=America/Los_Angeles:20260704T221000\r\n")
+	assert.Contains(t, unfolded, "DTSTAMP:20260704T070000Z\r\n")
+	assert.Contains(t, unfolded, "LOCATION:T-Mobile Park\r\n")
+	assert.Contains(t, unfolded, "URL:https://example.com/tickets\r\n")
+	assert.Contains(t, unfolded, `DESCRIPTION:Seattle Mariners are playing against the Houston Astros at T-Mobile Park. The game starts at 7:10 PM.\n\nTickets: https://example.com/tickets`+"\r\n")
+	assert.Contains(t, unfolded, `SUMMARY:CANCELLED: Some Band\, With Friends\; Live is at Climate Pledge Arena and they are going to play for a very long time`+"\r\n")
+	assert.Contains(t, unfolded, "STATUS:CANCELLED\r\n")
+}
+
+func TestRenderICS_TBA(t *testing.T) {
+	today := time.Date(2026, time.July, 4, 0, 0, 0, 0, events.SeattleTimeZone)
+	results := &events.EventResults{
+		TomorrowEvents: []*events.Event{
+			{
+				ID:        "ticketmaster:3",
+				TeamName:  "Seattle Sounders",
+				Opponent:  "Portland Timbers",
+				Venue:     "Lumen Field",
+				LocalTime: "TBA",
+				// the noon placeholder we sort by
+				RawTime: time.Date(2026, time.July, 5, 12, 0, 0, 0, events.SeattleTimeZone).Unix(),
+			},
+		},
+	}
+
+	ics := string(RenderICS(results, today))
+
+	// we don't know the time, so it's an all day event rather than a made up one
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20260705\r\n")
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:20260706\r\n")
+	assert.NotContains(t, ics, "DTSTART;TZID=")
+}
+
+func TestUID_Rescheduled(t *testing.T) {
+	e := &events.Event{ID: "espn:1", Venue: "T-Mobile Park", RawTime: time.Date(2026, time.July, 4, 19, 10, 0, 0, events.SeattleTimeZone).Unix()}
+	rescheduled := *e
+	rescheduled.RawTime = time.Date(2026, time.July, 5, 13, 10, 0, 0, events.SeattleTimeZone).Unix()
+
+	assert.Equal(t, UID(e), UID(&rescheduled))
+}
+
+func TestRenderTeamICS(t *testing.T) {
+	today := time.Date(2026, time.July, 4, 0, 0, 0, 0, events.SeattleTimeZone)
+	results := &events.EventResults{
+		TomorrowEvents: []*events.Event{
+			{ID: "espn:1", TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", RawTime: time.Date(2026, time.July, 5, 13, 10, 0, 0, events.SeattleTimeZone).Unix()},
+		},
+	}
+
+	output := RenderTeamICS("Seattle Mariners", results, today)
+	expected := RenderICS(results, today)
+
+	// the same feed, just named for the team
+	assert.Contains(t, string(output), "X-WR-CALNAME:Seattle Mariners home games\r\n")
+	assert.Equal(t, strings.Replace(string(expected), "X-WR-CALNAME:Is there a Seattle home game today?", "X-WR-CALNAME:Seattle Mariners home games", 1), string(output))
+}
@@ -0,0 +1,153 @@
+package renderimage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+const (
+	// OGKey is where the social preview image is published
+	OGKey = "og.png"
+
+	// Width and Height are the size Open Graph recommends for large previews
+	Width  = 1200
+	Height = 630
+
+	margin = 60
+
+	// maxEvents is how many events we list before giving up and saying how many more there are
+	maxEvents = 3
+)
+
+var (
+	backgroundColor = color.RGBA{R: 0x13, G: 0x17, B: 0x1f, A: 0xff}
+	textColor       = color.RGBA{R: 0xe0, G: 0xe3, B: 0xe7, A: 0xff}
+	mutedColor      = color.RGBA{R: 0x8d, G: 0x95, B: 0xa0, A: 0xff}
+	yesColor        = color.RGBA{R: 0x3f, G: 0xb9, B: 0x6e, A: 0xff}
+	noColor         = color.RGBA{R: 0xd9, G: 0x4f, B: 0x4f, A: 0xff}
+)
+
+var (
+	answerFace font.Face
+	headerFace font.Face
+	eventFace  font.Face
+)
+
+func newFace(ttf []byte, size float64) font.Face {
+	parsed, err := opentype.Parse(ttf)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not parse font")
+	}
+
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create font face")
+	}
+
+	return face
+}
+
+func init() {
+	answerFace = newFace(gobold.TTF, 200)
+	headerFace = newFace(gobold.TTF, 44)
+	eventFace = newFace(goregular.TTF, 34)
+}
+
+// drawText draws s with its baseline at y, cutting it off with an ellipsis if it won't fit between the margins
+func drawText(img draw.Image, face font.Face, c color.Color, y int, s string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(margin, y),
+	}
+
+	maxWidth := fixed.I(Width - 2*margin)
+	if d.MeasureString(s) > maxWidth {
+		runes := []rune(s)
+		for len(runes) > 0 && d.MeasureString(string(runes)+"…") > maxWidth {
+			runes = runes[:len(runes)-1]
+		}
+		s = string(runes) + "…"
+	}
+
+	d.DrawString(s)
+}
+
+func eventLines(results *events.EventResults) []string {
+	var happening []*events.Event
+	for _, curr := range events.CollapseSessions(results.TodayEvent) {
+		if curr.IsHappening() {
+			happening = append(happening, curr)
+		}
+	}
+
+	var lines []string
+	for i, curr := range happening {
+		if i == maxEvents {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(happening)-maxEvents))
+			break
+		}
+
+		line := curr.CalendarSummary()
+		if curr.LocalTime != "" {
+			line = fmt.Sprintf("%s · %s", line, curr.LocalTime)
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// RenderOGImage renders the preview image that shows up when the site is shared. It has the answer, the date and the
+// first few things happening today.
+func RenderOGImage(results *events.EventResults, seattleToday time.Time) ([]byte, error) {
+	return RenderPreviewImage("Seattle home games", results, seattleToday)
+}
+
+// RenderPreviewImage renders a preview image for a page that only covers some events, like a venue's or a team's.
+// subject is what the page is about, and results should already be filtered down to it.
+func RenderPreviewImage(subject string, results *events.EventResults, seattleToday time.Time) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	answer, answerColor := "NO", noColor
+	if events.AnyHappening(results.TodayEvent) {
+		answer, answerColor = "YES", yesColor
+	}
+
+	drawText(img, answerFace, answerColor, 230, answer)
+	drawText(img, headerFace, mutedColor, 310, fmt.Sprintf("%s · %s", subject, seattleToday.Format("Monday, January 2, 2006")))
+
+	y := 400
+	for _, curr := range eventLines(results) {
+		drawText(img, eventFace, textColor, y, curr)
+		y += 56
+	}
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return nil, fmt.Errorf("renderPreviewImage: could not encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
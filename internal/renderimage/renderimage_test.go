@@ -0,0 +1,64 @@
+package renderimage
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func hasColor(img image.Image, c color.RGBA) bool {
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestRenderOGImage(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+
+	results := &events.EventResults{
+		TodayEvent: []*events.Event{
+			{TeamName: "Seattle Mariners", Opponent: "Houston Astros", Venue: "T-Mobile Park", LocalTime: "7:10 PM"},
+		},
+	}
+
+	payload, err := RenderOGImage(results, seattleToday)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(payload))
+	require.NoError(t, err)
+	assert.Equal(t, Width, img.Bounds().Dx())
+	assert.Equal(t, Height, img.Bounds().Dy())
+	assert.True(t, hasColor(img, yesColor))
+	assert.False(t, hasColor(img, noColor))
+
+	payload, err = RenderOGImage(&events.EventResults{}, seattleToday)
+	require.NoError(t, err)
+
+	img, err = png.Decode(bytes.NewReader(payload))
+	require.NoError(t, err)
+	assert.True(t, hasColor(img, noColor))
+}
+
+func TestEventLines(t *testing.T) {
+	results := &events.EventResults{}
+	for range 5 {
+		results.TodayEvent = append(results.TodayEvent, &events.Event{ShortDescription: "Something", LocalTime: "8:00 PM"})
+	}
+	results.TodayEvent = append(results.TodayEvent, &events.Event{ShortDescription: "Nope", Status: events.StatusCancelled})
+
+	lines := eventLines(results)
+	assert.Equal(t, []string{"Something · 8:00 PM", "Something · 8:00 PM", "Something · 8:00 PM", "…and 2 more"}, lines)
+}
@@ -8,38 +8,57 @@ import (
 	"github.com/lthummus/seattle-sports-today/internal/events"
 )
 
-func renderEventSlice(x []*events.Event) []map[string]any {
-	renderableEvents := make([]map[string]any, len(x))
+// legacyResponse is the format of todays_events.json. It is frozen so the people already parsing it don't break. New
+// fields go in the v2 format (see Response).
+type legacyResponse struct {
+	Date           string        `json:"date"`
+	Events         []legacyEvent `json:"events"`
+	TomorrowEvents []legacyEvent `json:"tomorrow_events"`
+}
+
+type legacyEvent struct {
+	ID          string             `json:"id"`
+	Key         string             `json:"key"`
+	Description string             `json:"description"`
+	Venue       string             `json:"venue,omitempty"`
+	TeamName    string             `json:"team_name,omitempty"`
+	Opponent    string             `json:"opponent,omitempty"`
+	LocalTime   string             `json:"local_time,omitempty"`
+	UnixTime    int64              `json:"unix_time,omitempty"`
+	Session     *events.Session    `json:"session,omitempty"`
+	Status      events.EventStatus `json:"status"`
+	Links       []events.Link      `json:"links,omitempty"`
+	ImageURL    string             `json:"image_url,omitempty"`
+}
+
+func renderEventSlice(x []*events.Event) []legacyEvent {
+	renderableEvents := make([]legacyEvent, len(x))
 
 	for i, curr := range x {
-		e := map[string]any{}
-		e["description"] = curr.String()
-		if curr.Venue != "" {
-			e["venue"] = curr.Venue
-		}
-		if curr.TeamName != "" {
-			e["team_name"] = curr.TeamName
+		renderableEvents[i] = legacyEvent{
+			ID:          curr.ID,
+			Key:         curr.Key(),
+			Description: curr.String(),
+			Venue:       curr.Venue,
+			TeamName:    curr.TeamName,
+			Opponent:    curr.Opponent,
+			LocalTime:   curr.LocalTime,
+			UnixTime:    curr.RawTime,
+			Session:     curr.Session,
+			Status:      curr.Status.Normalized(),
+			Links:       curr.Links,
+			ImageURL:    curr.ImageURL,
 		}
-		if curr.Opponent != "" {
-			e["opponent"] = curr.Opponent
-		}
-		if curr.LocalTime != "" {
-			e["local_time"] = curr.LocalTime
-		}
-		if curr.RawTime != 0 {
-			e["unix_time"] = curr.RawTime
-		}
-		renderableEvents[i] = e
 	}
 
 	return renderableEvents
 }
 
 func RenderJSON(results *events.EventResults, seattleToday time.Time) ([]byte, error) {
-	data := map[string]any{
-		"date":            seattleToday.Format("2006-01-02"),
-		"events":          renderEventSlice(results.TodayEvent),
-		"tomorrow_events": renderEventSlice(results.TomorrowEvents),
+	data := legacyResponse{
+		Date:           seattleToday.Format("2006-01-02"),
+		Events:         renderEventSlice(results.TodayEvent),
+		TomorrowEvents: renderEventSlice(results.TomorrowEvents),
 	}
 
 	payload, err := json.Marshal(data)
@@ -49,3 +68,21 @@ func RenderJSON(results *events.EventResults, seattleToday time.Time) ([]byte, e
 
 	return payload, nil
 }
+
+// RenderJSONV2 renders today's and tomorrow's events in the v2 format, which is described by the schema from
+// RenderSchema
+func RenderJSONV2(results *events.EventResults, seattleToday time.Time) ([]byte, error) {
+	data := Response{
+		SchemaVersion:  SchemaVersion,
+		Date:           seattleToday.Format("2006-01-02"),
+		Events:         newEventSlice(results.TodayEvent),
+		TomorrowEvents: newEventSlice(results.TomorrowEvents),
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("renderJSONV2: could not render: %w", err)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,152 @@
+package renderjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func testResults() *events.EventResults {
+	start := time.Date(2026, time.May, 2, 19, 10, 0, 0, events.SeattleTimeZone)
+	return &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				ID:        "espn:1234",
+				TeamName:  "Seattle Mariners",
+				Venue:     "T-Mobile Park",
+				LocalTime: "7:10 PM",
+				Opponent:  "Houston Astros",
+				RawTime:   start.Unix(),
+				Category:  events.CategorySports,
+				Session:   &events.Session{Kind: events.SessionKindDoubleheader, GroupID: "abc", Number: 1, Count: 2},
+				Links:     []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com/tickets"}},
+			},
+		},
+		TomorrowEvents: []*events.Event{
+			{
+				ID:               "special:2026-05-03-something",
+				ShortDescription: "Something is happening",
+			},
+		},
+	}
+}
+
+// checkAgainstSchema is a tiny JSON Schema validator that understands the subset of the spec that schemaFor generates
+func checkAgainstSchema(t *testing.T, path string, schema map[string]any, value any) {
+	t.Helper()
+
+	types := map[string]bool{}
+	switch st := schema["type"].(type) {
+	case string:
+		types[st] = true
+	case []any:
+		for _, curr := range st {
+			types[curr.(string)] = true
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		assert.True(t, types["null"], "%s: unexpected null", path)
+	case string:
+		assert.True(t, types["string"], "%s: unexpected string", path)
+		if enum, ok := schema["enum"].([]any); ok {
+			assert.Contains(t, enum, v, path)
+		}
+	case float64:
+		assert.True(t, types["integer"], "%s: unexpected number", path)
+		assert.Equal(t, float64(int64(v)), v, path)
+	case []any:
+		require.True(t, types["array"], "%s: unexpected array", path)
+		for i, curr := range v {
+			checkAgainstSchema(t, fmt.Sprintf("%s[%d]", path, i), schema["items"].(map[string]any), curr)
+		}
+	case map[string]any:
+		require.True(t, types["object"], "%s: unexpected object", path)
+		properties := schema["properties"].(map[string]any)
+		for _, curr := range schema["required"].([]any) {
+			assert.Contains(t, v, curr, "%s: missing required property", path)
+		}
+		for key, curr := range v {
+			propertySchema, ok := properties[key].(map[string]any)
+			if !assert.True(t, ok, "%s: unexpected property %s", path, key) {
+				continue
+			}
+			checkAgainstSchema(t, path+"."+key, propertySchema, curr)
+		}
+	default:
+		t.Errorf("%s: unexpected value %v", path, v)
+	}
+}
+
+func TestRenderJSONV2(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+
+	payload, err := RenderJSONV2(testResults(), seattleToday)
+	require.NoError(t, err)
+
+	schemaPayload, err := RenderSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(schemaPayload, &schema))
+	assert.Equal(t, schemaDialect, schema["$schema"])
+
+	var rendered map[string]any
+	require.NoError(t, json.Unmarshal(payload, &rendered))
+	checkAgainstSchema(t, "$", schema, rendered)
+
+	var response Response
+	require.NoError(t, json.Unmarshal(payload, &response))
+	assert.Equal(t, SchemaVersion, response.SchemaVersion)
+	assert.Equal(t, "2026-05-02", response.Date)
+
+	require.Len(t, response.Events, 1)
+	assert.Equal(t, "sports", response.Events[0].Category)
+	assert.Equal(t, "scheduled", response.Events[0].Status)
+	assert.Equal(t, "2026-05-02T19:10:00-07:00", response.Events[0].StartTime)
+	assert.Equal(t, "2026-05-02T22:10:00-07:00", response.Events[0].EndTime)
+	require.NotNil(t, response.Events[0].Session)
+	assert.Equal(t, 2, response.Events[0].Session.Count)
+
+	// fields we don't have values for are still there
+	require.Len(t, response.TomorrowEvents, 1)
+	assert.Equal(t, "other", response.TomorrowEvents[0].Category)
+	assert.Nil(t, response.TomorrowEvents[0].Session)
+	assert.Contains(t, string(payload), `"unix_time":0`)
+	assert.Contains(t, string(payload), `"links":[]`)
+}
+
+func TestRenderJSON_LegacyFormat(t *testing.T) {
+	seattleToday := time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+
+	payload, err := RenderJSON(testResults(), seattleToday)
+	require.NoError(t, err)
+
+	var rendered map[string]any
+	require.NoError(t, json.Unmarshal(payload, &rendered))
+
+	assert.NotContains(t, rendered, "schema_version")
+	assert.Equal(t, "2026-05-02", rendered["date"])
+
+	tomorrow := rendered["tomorrow_events"].([]any)[0].(map[string]any)
+	assert.ElementsMatch(t, []string{"id", "key", "description", "status"}, keys(tomorrow))
+
+	today := rendered["events"].([]any)[0].(map[string]any)
+	assert.NotContains(t, today, "category")
+	assert.Equal(t, "7:10 PM", today["local_time"])
+}
+
+func keys(m map[string]any) []string {
+	var x []string
+	for k := range m {
+		x = append(x, k)
+	}
+	return x
+}
@@ -0,0 +1,88 @@
+package renderjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lthummus/seattle-sports-today/internal/site"
+)
+
+const (
+	schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+	schemaID      = site.URL + SchemaKey
+)
+
+// schemaFor builds a JSON Schema for the given type from its struct tags. It only handles the handful of kinds our
+// response types use. Every field is required, since the v2 format always includes every field. Pointers can also be
+// null. The enum, format and description tags are copied in to the schema.
+func schemaFor(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := schemaFor(t.Elem())
+		schema["type"] = []string{schema["type"].(string), "null"}
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{
+			"type":  "array",
+			"items": schemaFor(t.Elem()),
+		}
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			property := schemaFor(field.Type)
+			if enum := field.Tag.Get("enum"); enum != "" {
+				property["enum"] = strings.Split(enum, ",")
+			}
+			if format := field.Tag.Get("format"); format != "" {
+				property["format"] = format
+			}
+			if description := field.Tag.Get("description"); description != "" {
+				property["description"] = description
+			}
+
+			properties[name] = property
+			required = append(required, name)
+		}
+
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	default:
+		panic(fmt.Sprintf("renderjson: schemaFor: unsupported kind %s", t.Kind()))
+	}
+}
+
+// RenderSchema renders the JSON Schema document for the v2 format
+func RenderSchema() ([]byte, error) {
+	schema := schemaFor(reflect.TypeFor[Response]())
+	schema["$schema"] = schemaDialect
+	schema["$id"] = schemaID
+	schema["title"] = "Is there a Seattle home game today?"
+	schema["description"] = fmt.Sprintf("Today's and tomorrow's events in Seattle. Schema version %d.", SchemaVersion)
+
+	payload, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("renderSchema: could not render: %w", err)
+	}
+
+	return payload, nil
+}
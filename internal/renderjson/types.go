@@ -0,0 +1,109 @@
+package renderjson
+
+import (
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+const (
+	// SchemaVersion is bumped any time the v2 format changes in a way consumers might care about
+	SchemaVersion = 2
+
+	// V2Key is where the v2 JSON is published. The original todays_events.json keeps its old format so existing
+	// consumers don't break.
+	V2Key = "v2/todays_events.json"
+
+	// SchemaKey is where the JSON Schema describing the v2 format is published
+	SchemaKey = "v2/schema.json"
+)
+
+// Response is the top level of the v2 JSON
+type Response struct {
+	SchemaVersion  int     `json:"schema_version" description:"Version of this format. Bumped on any change consumers might care about."`
+	Date           string  `json:"date" format:"date" description:"Today's date in Seattle"`
+	Events         []Event `json:"events" description:"Events happening today"`
+	TomorrowEvents []Event `json:"tomorrow_events" description:"Events happening tomorrow"`
+}
+
+// Event is a single event in the v2 JSON. Every field is always present. Fields we don't have a value for are empty
+// strings (or null for session).
+type Event struct {
+	ID          string   `json:"id" description:"Source specific event ID, prefixed with the source"`
+	Key         string   `json:"key" description:"Stable ID for the event built from its venue, date and start time"`
+	Category    string   `json:"category" enum:"sports,music,other"`
+	Status      string   `json:"status" enum:"scheduled,postponed,rescheduled,cancelled"`
+	Description string   `json:"description" description:"Human readable description of the event"`
+	Venue       string   `json:"venue"`
+	TeamName    string   `json:"team_name" description:"Seattle team playing, empty for non-sporting events"`
+	Opponent    string   `json:"opponent"`
+	HomeAway    string   `json:"home_away" enum:"home,away,neutral," description:"Whether the Seattle team is home or away, or if it's a neutral site game. Empty if we don't know."`
+	LocalTime   string   `json:"local_time" description:"Start time in Seattle (e.g. 7:10 PM), or TBA"`
+	UnixTime    int64    `json:"unix_time" description:"Start time as seconds since the unix epoch"`
+	StartTime   string   `json:"start_time" format:"date-time"`
+	EndTime     string   `json:"end_time" format:"date-time" description:"Estimated, none of our sources tell us when things end"`
+	Session     *Session `json:"session" description:"Set when this event is one of a doubleheader or a multi-day event"`
+	Links       []Link   `json:"links"`
+	ImageURL    string   `json:"image_url" description:"Empty if we don't have an image"`
+}
+
+// Session links an event to the other sessions in its group
+type Session struct {
+	Kind    string `json:"kind" enum:"doubleheader,multi_day"`
+	GroupID string `json:"group_id"`
+	Number  int    `json:"number" description:"Game number for a doubleheader or day number for a multi-day event"`
+	Count   int    `json:"count"`
+}
+
+// Link is an outbound link for an event
+type Link struct {
+	Kind string `json:"kind" enum:"tickets,team,info"`
+	URL  string `json:"url" format:"uri"`
+}
+
+func newEvent(e *events.Event) Event {
+	var session *Session
+	if e.Session != nil {
+		session = &Session{
+			Kind:    string(e.Session.Kind),
+			GroupID: e.Session.GroupID,
+			Number:  e.Session.Number,
+			Count:   e.Session.Count,
+		}
+	}
+
+	links := make([]Link, len(e.Links))
+	for i, curr := range e.Links {
+		links[i] = Link{
+			Kind: string(curr.Kind),
+			URL:  curr.URL,
+		}
+	}
+
+	return Event{
+		ID:          e.ID,
+		Key:         e.Key(),
+		Category:    string(e.Category.Normalized()),
+		Status:      string(e.Status.Normalized()),
+		Description: e.String(),
+		Venue:       e.Venue,
+		TeamName:    e.TeamName,
+		Opponent:    e.Opponent,
+		HomeAway:    string(e.HomeAway),
+		LocalTime:   e.LocalTime,
+		UnixTime:    e.RawTime,
+		StartTime:   e.StartTime().Format(time.RFC3339),
+		EndTime:     e.EndTime().Format(time.RFC3339),
+		Session:     session,
+		Links:       links,
+		ImageURL:    e.ImageURL,
+	}
+}
+
+func newEventSlice(x []*events.Event) []Event {
+	rendered := make([]Event, len(x))
+	for i, curr := range x {
+		rendered[i] = newEvent(curr)
+	}
+	return rendered
+}
@@ -0,0 +1,95 @@
+package rendersitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/site"
+)
+
+const (
+	// SitemapKey and RobotsKey are where the sitemap and robots.txt are published, at the root of the site
+	SitemapKey = "sitemap.xml"
+	RobotsKey  = "robots.txt"
+)
+
+// disallowed are pages that we publish but don't want showing up in search results on their own
+var disallowed = []string{"/widget.html", "/releases/"}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// RenderSitemap renders a sitemap of every page published at keys, marked as modified today. Pages from the previously
+// published sitemap (which may be nil) that weren't published this time, like older archive days, keep the date they
+// were last modified.
+func RenderSitemap(previous []byte, keys []string, seattleToday time.Time) ([]byte, error) {
+	var previousSitemap urlSet
+	err := site.DecodePrevious(previous, xml.Unmarshal, &previousSitemap)
+	if err != nil {
+		return nil, fmt.Errorf("renderSitemap: could not read previous sitemap: %w", err)
+	}
+
+	lastMod := seattleToday.Format(time.DateOnly)
+
+	published := map[string]bool{}
+	var urls []sitemapURL
+	for _, curr := range keys {
+		loc := site.PageURL(curr)
+		if published[loc] {
+			continue
+		}
+		published[loc] = true
+		urls = append(urls, sitemapURL{Loc: loc, LastMod: lastMod})
+	}
+
+	for _, curr := range previousSitemap.URLs {
+		// pages used to be listed by their directory, which CloudFront only serves at the root. They're listed in full
+		// now, so the old entries are dropped.
+		if curr.Loc != site.URL && strings.HasSuffix(curr.Loc, "/") {
+			continue
+		}
+		if !published[curr.Loc] {
+			published[curr.Loc] = true
+			urls = append(urls, curr)
+		}
+	}
+
+	slices.SortFunc(urls, func(a, b sitemapURL) int {
+		return strings.Compare(a.Loc, b.Loc)
+	})
+
+	buf := bytes.NewBufferString(xml.Header)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	err = enc.Encode(urlSet{URLs: urls})
+	if err != nil {
+		return nil, fmt.Errorf("renderSitemap: could not render: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RenderRobots renders a robots.txt that lets everyone crawl the site and points them at the sitemap
+func RenderRobots() []byte {
+	var sb strings.Builder
+
+	sb.WriteString("User-agent: *\n")
+	for _, curr := range disallowed {
+		fmt.Fprintf(&sb, "Disallow: %s\n", curr)
+	}
+	sb.WriteString("Allow: /\n\n")
+	fmt.Fprintf(&sb, "Sitemap: %s%s\n", site.URL, SitemapKey)
+
+	return []byte(sb.String())
+}
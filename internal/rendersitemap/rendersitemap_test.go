@@ -0,0 +1,53 @@
+package rendersitemap
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+func TestRenderSitemap(t *testing.T) {
+	day1 := time.Date(2026, time.November, 1, 3, 14, 0, 0, events.SeattleTimeZone)
+	day2 := day1.AddDate(0, 0, 1)
+
+	first, err := RenderSitemap(nil, []string{"index.html", "archive/2026/11/01.html", "archive/2026/11/index.html"}, day1)
+	require.NoError(t, err)
+
+	second, err := RenderSitemap(first, []string{"index.html", "archive/2026/11/02.html", "archive/2026/11/index.html", "index.html"}, day2)
+	require.NoError(t, err)
+
+	var sitemap urlSet
+	require.NoError(t, xml.Unmarshal(second, &sitemap))
+
+	assert.Equal(t, []sitemapURL{
+		{Loc: "https://isthereaseattlehomegametoday.com/", LastMod: "2026-11-02"},
+		// yesterday's page wasn't published again, so it keeps its date
+		{Loc: "https://isthereaseattlehomegametoday.com/archive/2026/11/01.html", LastMod: "2026-11-01"},
+		{Loc: "https://isthereaseattlehomegametoday.com/archive/2026/11/02.html", LastMod: "2026-11-02"},
+		{Loc: "https://isthereaseattlehomegametoday.com/archive/2026/11/index.html", LastMod: "2026-11-02"},
+	}, sitemap.URLs)
+
+	// pages listed by their directory before are replaced with their full URL
+	old := `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>https://isthereaseattlehomegametoday.com/venue/lumen-field/</loc><lastmod>2026-11-01</lastmod></url></urlset>`
+	third, err := RenderSitemap([]byte(old), []string{"index.html", "venue/lumen-field/index.html"}, day2)
+	require.NoError(t, err)
+	sitemap = urlSet{}
+	require.NoError(t, xml.Unmarshal(third, &sitemap))
+	assert.Equal(t, []sitemapURL{
+		{Loc: "https://isthereaseattlehomegametoday.com/", LastMod: "2026-11-02"},
+		{Loc: "https://isthereaseattlehomegametoday.com/venue/lumen-field/index.html", LastMod: "2026-11-02"},
+	}, sitemap.URLs)
+
+	// a sitemap we can't read isn't replaced with one that has only today's pages
+	_, err = RenderSitemap([]byte("<not a sitemap"), []string{"index.html"}, day1)
+	assert.ErrorContains(t, err, "could not read previous sitemap")
+}
+
+func TestRenderRobots(t *testing.T) {
+	assert.Equal(t, "User-agent: *\nDisallow: /widget.html\nDisallow: /releases/\nAllow: /\n\nSitemap: https://isthereaseattlehomegametoday.com/sitemap.xml\n", string(RenderRobots()))
+}
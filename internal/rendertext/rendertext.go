@@ -0,0 +1,118 @@
+package rendertext
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+const (
+	// TextKey is where the plain text summary is published
+	TextKey = "today.txt"
+
+	question   = "Is there a Seattle home game today?"
+	dateFormat = "Monday Jan _2, 2006"
+
+	nothingToday    = "Nothing is scheduled today."
+	nothingTomorrow = "Nothing is scheduled tomorrow (yet?)."
+)
+
+func answer(results *events.EventResults) string {
+	if events.AnyHappening(results.TodayEvent) {
+		return "YES"
+	}
+	return "NO"
+}
+
+func textLine(e *events.Event) string {
+	if label := e.StatusLabel(); label != "" {
+		return fmt.Sprintf("%s: %s", label, e.String())
+	}
+	return e.String()
+}
+
+func writeTextSection(sb *strings.Builder, heading string, empty string, x []*events.Event) {
+	if len(x) == 0 {
+		sb.WriteString(empty)
+		sb.WriteString("\n")
+		return
+	}
+
+	sb.WriteString(heading)
+	sb.WriteString(":\n")
+	for _, curr := range events.CollapseSessions(x) {
+		sb.WriteString("- ")
+		sb.WriteString(textLine(curr))
+		sb.WriteString("\n")
+	}
+}
+
+// RenderText renders a compact plain text summary of today and tomorrow, suitable for chat bots and terminals
+func RenderText(results *events.EventResults, seattleToday time.Time) []byte {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s %s\n", question, answer(results))
+	sb.WriteString(seattleToday.Format(dateFormat))
+	sb.WriteString("\n\n")
+
+	writeTextSection(&sb, "Today", nothingToday, results.TodayEvent)
+	sb.WriteString("\n")
+	writeTextSection(&sb, "Tomorrow", nothingTomorrow, results.TomorrowEvents)
+
+	return []byte(sb.String())
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"#", `\#`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+func markdownLine(e *events.Event) string {
+	var sb strings.Builder
+	if label := e.StatusLabel(); label != "" {
+		fmt.Fprintf(&sb, "**%s** ", label)
+	}
+	sb.WriteString(markdownEscaper.Replace(e.String()))
+	if link := e.PrimaryLink(); link != nil {
+		fmt.Fprintf(&sb, " ([%s](%s))", link.Label(), link.URL)
+	}
+	return sb.String()
+}
+
+func writeMarkdownSection(sb *strings.Builder, heading string, empty string, x []*events.Event) {
+	fmt.Fprintf(sb, "## %s\n\n", heading)
+	if len(x) == 0 {
+		fmt.Fprintf(sb, "_%s_\n", empty)
+		return
+	}
+
+	for _, curr := range events.CollapseSessions(x) {
+		sb.WriteString("- ")
+		sb.WriteString(markdownLine(curr))
+		sb.WriteString("\n")
+	}
+}
+
+// RenderMarkdown renders the same summary as RenderText as Markdown, with links to tickets or more info where we have
+// them
+func RenderMarkdown(results *events.EventResults, seattleToday time.Time) []byte {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# %s **%s**\n\n", question, answer(results))
+	fmt.Fprintf(&sb, "_%s_\n\n", seattleToday.Format(dateFormat))
+
+	writeMarkdownSection(&sb, "Today", nothingToday, results.TodayEvent)
+	sb.WriteString("\n")
+	writeMarkdownSection(&sb, "Tomorrow", nothingTomorrow, results.TomorrowEvents)
+
+	return []byte(sb.String())
+}
@@ -0,0 +1,61 @@
+package rendertext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lthummus/seattle-sports-today/internal/events"
+)
+
+var seattleToday = time.Date(2026, time.May, 2, 0, 0, 0, 0, events.SeattleTimeZone)
+
+func testResults() *events.EventResults {
+	return &events.EventResults{
+		TodayEvent: []*events.Event{
+			{
+				ShortDescription: "Some_Band is at WAMU Theater",
+				RawDescription:   "Some_Band is at WAMU Theater. It starts at 8:00 PM",
+				Links:            []events.Link{{Kind: events.LinkKindTickets, URL: "https://example.com/tickets"}},
+			},
+			{
+				ShortDescription: "Another Band is at Climate Pledge Arena",
+				RawDescription:   "Another Band is at Climate Pledge Arena. It starts at 7:00 PM",
+				Status:           events.StatusPostponed,
+			},
+		},
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	expected := `Is there a Seattle home game today? YES
+Saturday May  2, 2026
+
+Today:
+- Some_Band is at WAMU Theater. It starts at 8:00 PM
+- POSTPONED: Another Band is at Climate Pledge Arena. It starts at 7:00 PM
+
+Nothing is scheduled tomorrow (yet?).
+`
+	assert.Equal(t, expected, string(RenderText(testResults(), seattleToday)))
+
+	assert.Contains(t, string(RenderText(&events.EventResults{}, seattleToday)), "Is there a Seattle home game today? NO\n")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	expected := `# Is there a Seattle home game today? **YES**
+
+_Saturday May  2, 2026_
+
+## Today
+
+- Some\_Band is at WAMU Theater. It starts at 8:00 PM ([Tickets](https://example.com/tickets))
+- **POSTPONED** Another Band is at Climate Pledge Arena. It starts at 7:00 PM
+
+## Tomorrow
+
+_Nothing is scheduled tomorrow (yet?)._
+`
+	assert.Equal(t, expected, string(RenderMarkdown(testResults(), seattleToday)))
+}
@@ -0,0 +1,29 @@
+package site
+
+// URL is the root of the published site
+const URL = "https://isthereaseattlehomegametoday.com/"
+
+// rootIndexKey is the page CloudFront serves at the root of the site, as its default root object
+const rootIndexKey = "index.html"
+
+// PageURL is the public URL of the page published at key. Only the main page is linked by its directory, since
+// CloudFront only serves the default root object at the root. Everything else, like venue/lumen-field/index.html, is
+// linked in full so it works without anything rewriting directory paths.
+func PageURL(key string) string {
+	if key == rootIndexKey {
+		return URL
+	}
+	return URL + key
+}
+
+// DecodePrevious decodes a previously published artifact in to v for renderers that build on what is already live.
+// previous is nil when nothing has been published yet, in which case v is left as it is so the renderer starts fresh.
+// Anything else has to decode. An artifact we can't read is an error rather than a fresh start, since starting over
+// would throw away everything that was live.
+func DecodePrevious(previous []byte, unmarshal func([]byte, any) error, v any) error {
+	if previous == nil {
+		return nil
+	}
+
+	return unmarshal(previous, v)
+}
@@ -0,0 +1,35 @@
+package site
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePrevious(t *testing.T) {
+	type previous struct {
+		Days []string `json:"days"`
+	}
+
+	// nothing published yet leaves the defaults alone
+	v := previous{Days: []string{"default"}}
+	require.NoError(t, DecodePrevious(nil, json.Unmarshal, &v))
+	assert.Equal(t, []string{"default"}, v.Days)
+
+	require.NoError(t, DecodePrevious([]byte(`{"days":["2026-05-01","2026-05-02"]}`), json.Unmarshal, &v))
+	assert.Equal(t, []string{"2026-05-01", "2026-05-02"}, v.Days)
+
+	// an empty artifact was published, it just can't be read
+	assert.Error(t, DecodePrevious([]byte{}, json.Unmarshal, &v))
+	assert.Error(t, DecodePrevious([]byte("this is not json"), json.Unmarshal, &v))
+}
+
+func TestPageURL(t *testing.T) {
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/", PageURL("index.html"))
+	// only the root is served as a directory, so everything else is linked in full
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/es/index.html", PageURL("es/index.html"))
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/venue/lumen-field/index.html", PageURL("venue/lumen-field/index.html"))
+	assert.Equal(t, "https://isthereaseattlehomegametoday.com/archive/2026/11/01.html", PageURL("archive/2026/11/01.html"))
+}
@@ -0,0 +1,185 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"golang.org/x/sync/errgroup"
+)
+
+// EnvVarPublishCompressed turns on publishing compressed variants of every artifact that compresses well
+const EnvVarPublishCompressed = "PUBLISH_COMPRESSED"
+
+// compressibleTypes are the media types worth compressing. Images are already compressed.
+var compressibleTypes = []string{"text/html", "application/json", "application/schema+json", "text/calendar"}
+
+// compressibleExtensions are the extensions of the keys we publish with those types. Hash only has the key to go on.
+var compressibleExtensions = []string{".html", ".json", ".ics"}
+
+// encoding is a way of compressing artifacts, and the extension its variants are published with
+type encoding struct {
+	name      string
+	extension string
+	compress  func(contents []byte) ([]byte, error)
+}
+
+var encodings = []encoding{
+	{name: "br", extension: ".br", compress: compressBrotli},
+	{name: "gzip", extension: ".gz", compress: compressGzip},
+}
+
+func compressBrotli(contents []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	w := brotli.NewWriterLevel(buf, brotli.BestCompression)
+	_, err := w.Write(contents)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func compressGzip(contents []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	w, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(contents)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func compressible(a Artifact) bool {
+	if a.ContentEncoding != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(a.ContentType)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(compressibleTypes, mediaType)
+}
+
+// Compressed wraps a publisher so every HTML, JSON and calendar artifact is also published pre-compressed with brotli
+// and gzip, next to the original (index.html.br and index.html.gz). The variants keep the original's content type and
+// get the matching Content-Encoding. S3 can't set a Vary header, so whatever picks a variant based on Accept-Encoding
+// (like a CloudFront function rewriting the path) also needs to add Vary: Accept-Encoding to the response.
+type Compressed struct {
+	Publisher
+
+	mu sync.Mutex
+	// variants is the paths of the variants published for each path, so they get invalidated along with it
+	variants map[string][]string
+}
+
+func NewCompressed(publisher Publisher) *Compressed {
+	return &Compressed{
+		Publisher: publisher,
+		variants:  map[string][]string{},
+	}
+}
+
+// compressedVariants returns the compressed variants of the artifact
+func compressedVariants(a Artifact) ([]Artifact, error) {
+	variants := make([]Artifact, len(encodings))
+	for i, curr := range encodings {
+		contents, err := curr.compress(a.Contents)
+		if err != nil {
+			return nil, fmt.Errorf("could not %s compress %s: %w", curr.name, a.Key, err)
+		}
+
+		variants[i] = Artifact{
+			Key:             a.Key + curr.extension,
+			ContentType:     a.ContentType,
+			ContentEncoding: curr.name,
+			CacheControl:    a.CacheControl,
+			Contents:        contents,
+		}
+	}
+	return variants, nil
+}
+
+// Put publishes the artifact and, if it compresses well, its compressed variants
+func (c *Compressed) Put(ctx context.Context, artifact Artifact) error {
+	if !compressible(artifact) {
+		return c.Publisher.Put(ctx, artifact)
+	}
+
+	variants, err := compressedVariants(artifact)
+	if err != nil {
+		return fmt.Errorf("uploader: Compressed.Put: %w", err)
+	}
+
+	// the variants go up first so nothing ever sees a new original next to old variants that don't match it
+	eg, ctx2 := errgroup.WithContext(ctx)
+	paths := make([]string, len(variants))
+	for i, curr := range variants {
+		eg.Go(func() error {
+			return c.Publisher.Put(ctx2, curr)
+		})
+		paths[i] = "/" + curr.Key
+	}
+	err = eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.variants["/"+artifact.Key] = paths
+	c.mu.Unlock()
+
+	return c.Publisher.Put(ctx, artifact)
+}
+
+// Hash is the hash of the original, as long as all of its variants have been published too. That way turning on
+// compression publishes the variants of everything on the next run, even if nothing else changed.
+func (c *Compressed) Hash(ctx context.Context, key string) (string, error) {
+	hash, err := c.Publisher.Hash(ctx, key)
+	if err != nil || hash == "" || !slices.Contains(compressibleExtensions, path.Ext(key)) {
+		return hash, err
+	}
+
+	for _, curr := range encodings {
+		variantHash, err := c.Publisher.Hash(ctx, key+curr.extension)
+		if err != nil {
+			return "", err
+		}
+		if variantHash == "" {
+			return "", nil
+		}
+	}
+
+	return hash, nil
+}
+
+// Invalidate invalidates the paths along with the paths of any variants we published for them. Since that triples the
+// number of paths, it can tip them over into invalidating everything.
+func (c *Compressed) Invalidate(ctx context.Context, paths []string) error {
+	c.mu.Lock()
+	var all []string
+	for _, curr := range paths {
+		all = append(all, curr)
+		all = append(all, c.variants[curr]...)
+	}
+	c.mu.Unlock()
+
+	return c.Publisher.Invalidate(ctx, invalidationPaths(all))
+}
@@ -0,0 +1,78 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressed(t *testing.T) {
+	page := bytes.Repeat([]byte("<p>Seattle Mariners are playing against the Houston Astros at T-Mobile Park.</p>\n"), 20)
+	artifacts := []Artifact{
+		{Key: "index.html", ContentType: "text/html", Contents: page},
+		{Key: "todays_events.ics", ContentType: "text/calendar; charset=utf-8", Contents: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")},
+		{Key: "og.png", ContentType: "image/png", Contents: []byte("not really a png")},
+	}
+
+	memory := NewMemory()
+	require.NoError(t, Upload(context.Background(), NewCompressed(memory), artifacts, false))
+
+	assert.Equal(t, []string{"index.html", "index.html.br", "index.html.gz", "og.png", "todays_events.ics", "todays_events.ics.br", "todays_events.ics.gz"}, memory.Keys())
+
+	br, ok := memory.Object("index.html.br")
+	require.True(t, ok)
+	assert.Equal(t, "text/html", br.ContentType)
+	assert.Equal(t, "br", br.ContentEncoding)
+	assert.Equal(t, DefaultCachePolicy, br.CacheControl)
+	assert.Less(t, len(br.Contents), len(page))
+	decompressed, err := io.ReadAll(brotli.NewReader(bytes.NewReader(br.Contents)))
+	require.NoError(t, err)
+	assert.Equal(t, page, decompressed)
+
+	gz, ok := memory.Object("index.html.gz")
+	require.True(t, ok)
+	assert.Equal(t, "gzip", gz.ContentEncoding)
+	r, err := gzip.NewReader(bytes.NewReader(gz.Contents))
+	require.NoError(t, err)
+	decompressed, err = io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, page, decompressed)
+
+	require.Len(t, memory.Invalidations(), 1)
+	assert.ElementsMatch(t, []string{"/index.html", "/index.html.br", "/index.html.gz", "/todays_events.ics", "/todays_events.ics.br", "/todays_events.ics.gz", "/og.png"}, memory.Invalidations()[0])
+
+	// nothing changed, so nothing is published again
+	require.NoError(t, Upload(context.Background(), NewCompressed(memory), artifacts, false))
+	assert.Len(t, memory.Invalidations(), 1)
+}
+
+func TestCompressed_TurnedOnLater(t *testing.T) {
+	artifacts := []Artifact{{Key: "index.html", ContentType: "text/html", Contents: []byte("<p>YES</p>")}}
+
+	memory := NewMemory()
+	require.NoError(t, Upload(context.Background(), memory, artifacts, false))
+
+	// the page didn't change, but it doesn't have its variants yet
+	require.NoError(t, Upload(context.Background(), NewCompressed(memory), artifacts, false))
+	assert.Equal(t, []string{"index.html", "index.html.br", "index.html.gz"}, memory.Keys())
+	assert.ElementsMatch(t, []string{"/index.html", "/index.html.br", "/index.html.gz"}, memory.Invalidations()[1])
+}
+
+func TestCompressed_TooManyPaths(t *testing.T) {
+	// few enough to invalidate one by one, until their variants are added
+	var artifacts []Artifact
+	for i := range maxInvalidationPaths/3 + 1 {
+		artifacts = append(artifacts, Artifact{Key: fmt.Sprintf("page-%d.html", i), ContentType: "text/html", Contents: []byte("<p>YES</p>")})
+	}
+
+	memory := NewMemory()
+	require.NoError(t, Upload(context.Background(), NewCompressed(memory), artifacts, false))
+	assert.Equal(t, [][]string{{"/*"}}, memory.Invalidations())
+}
@@ -0,0 +1,113 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Dir publishes to a directory on disk, laid out the same way as the bucket, so the site can be previewed locally
+// (e.g. with `python3 -m http.server`). There's no cache in front of it, so invalidating does nothing, and writing a file
+// is cheap enough that we don't bother hashing and just write everything every time.
+type Dir struct {
+	root     string
+	readOnly bool
+}
+
+// NewDir creates a publisher for the given directory, creating it if needed
+func NewDir(root string) (*Dir, error) {
+	err := os.MkdirAll(root, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("uploader: NewDir: could not create %s: %w", root, err)
+	}
+
+	return &Dir{root: root}, nil
+}
+
+// OpenDir opens the given directory only to read what is published there, for dry runs. Unlike NewDir it doesn't create
+// the directory (one that doesn't exist just has nothing published yet), and it refuses to write or delete anything.
+func OpenDir(root string) *Dir {
+	return &Dir{root: root, readOnly: true}
+}
+
+// path is where key lives under the root. Keys come from us, but make sure one can't escape the directory anyway.
+func (p *Dir) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("key %s is outside of the publish directory", key)
+	}
+	return filepath.Join(p.root, filepath.FromSlash(key)), nil
+}
+
+func (p *Dir) Put(_ context.Context, artifact Artifact) error {
+	if p.readOnly {
+		return fmt.Errorf("uploader: Dir.Put: %s is open read only", p.root)
+	}
+
+	key := artifact.Key
+	path, err := p.path(key)
+	if err != nil {
+		return fmt.Errorf("uploader: Dir.Put: %w", err)
+	}
+
+	log.Info().Str("path", path).Str("content_type", artifact.ContentType).Msg("writing object")
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("uploader: Dir.Put: could not create directory for %s: %w", key, err)
+	}
+
+	err = os.WriteFile(path, artifact.Contents, 0o644) //#nosec G306 -- these are public web pages
+	if err != nil {
+		return fmt.Errorf("uploader: Dir.Put: could not write %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (p *Dir) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return nil, fmt.Errorf("uploader: Dir.Get: %w", err)
+	}
+
+	contents, err := os.ReadFile(path) //#nosec G304 -- path is checked to be under the root
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("uploader: Dir.Get: could not read %s: %w", key, err)
+	}
+
+	return contents, nil
+}
+
+func (p *Dir) Delete(_ context.Context, key string) error {
+	if p.readOnly {
+		return fmt.Errorf("uploader: Dir.Delete: %s is open read only", p.root)
+	}
+
+	path, err := p.path(key)
+	if err != nil {
+		return fmt.Errorf("uploader: Dir.Delete: %w", err)
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("uploader: Dir.Delete: could not delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (p *Dir) Hash(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (p *Dir) Invalidate(_ context.Context, _ []string) error {
+	return nil
+}
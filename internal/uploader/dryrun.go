@@ -0,0 +1,159 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/rs/zerolog/log"
+)
+
+// ChangeAction is what a dry run would have done to a key
+type ChangeAction string
+
+const (
+	ActionCreate ChangeAction = "create"
+	ActionUpdate ChangeAction = "update"
+	ActionDelete ChangeAction = "delete"
+)
+
+// diffContext is how many unchanged lines are shown around each change in a diff
+const diffContext = 2
+
+// Change is something a dry run would have changed
+type Change struct {
+	Key    string
+	Action ChangeAction
+
+	// Size is the size of what would have been published, and LiveSize the size of what is live now
+	Size     int
+	LiveSize int
+
+	// SameContents is set for updates whose contents match what is live. Either only how the artifact is served
+	// changed, or the publisher can't tell (Dir has no hashes) so it would be published again anyway.
+	SameContents bool
+
+	// Diff is a unified diff from what is live to what would have been published. It is only set for text artifacts
+	// that would be updated with different contents.
+	Diff string
+}
+
+// DryRun wraps a publisher so nothing ever changes. Reads go to the wrapped publisher, so everything is still compared
+// against what is live, but puts, deletes and invalidations are only recorded. Wrap it with Compressed (rather than the
+// other way around) to see the compressed variants that would be published too.
+type DryRun struct {
+	publisher Publisher
+
+	mu            sync.Mutex
+	changes       []Change
+	invalidations []string
+}
+
+func NewDryRun(publisher Publisher) *DryRun {
+	return &DryRun{publisher: publisher}
+}
+
+// isText is whether the content type is something worth diffing
+func isText(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") || strings.HasSuffix(mediaType, "xml")
+}
+
+func diff(key string, live []byte, rendered []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(live)),
+		B:        difflib.SplitLines(string(rendered)),
+		FromFile: "live/" + key,
+		ToFile:   "rendered/" + key,
+		Context:  diffContext,
+	})
+}
+
+func (d *DryRun) record(change Change) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.changes = append(d.changes, change)
+}
+
+// Put records that the artifact would have been published, diffing it against what is live
+func (d *DryRun) Put(ctx context.Context, artifact Artifact) error {
+	live, err := d.publisher.Get(ctx, artifact.Key)
+	if err != nil {
+		return err
+	}
+
+	change := Change{
+		Key:      artifact.Key,
+		Action:   ActionUpdate,
+		Size:     len(artifact.Contents),
+		LiveSize: len(live),
+	}
+	if live == nil {
+		change.Action = ActionCreate
+	} else if bytes.Equal(live, artifact.Contents) {
+		change.SameContents = true
+	} else if artifact.ContentEncoding == "" && isText(artifact.ContentType) {
+		change.Diff, err = diff(artifact.Key, live, artifact.Contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Debug().Str("key", artifact.Key).Str("action", string(change.Action)).Msg("dry run, not publishing")
+	d.record(change)
+	return nil
+}
+
+func (d *DryRun) Get(ctx context.Context, key string) ([]byte, error) {
+	return d.publisher.Get(ctx, key)
+}
+
+func (d *DryRun) Hash(ctx context.Context, key string) (string, error) {
+	return d.publisher.Hash(ctx, key)
+}
+
+// Delete records that key would have been deleted
+func (d *DryRun) Delete(_ context.Context, key string) error {
+	log.Debug().Str("key", key).Msg("dry run, not deleting")
+	d.record(Change{Key: key, Action: ActionDelete})
+	return nil
+}
+
+// Invalidate records the paths that would have been invalidated
+func (d *DryRun) Invalidate(_ context.Context, paths []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.invalidations = append(d.invalidations, paths...)
+	return nil
+}
+
+// Changes returns everything that would have been published or deleted, sorted by key
+func (d *DryRun) Changes() []Change {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	changes := slices.Clone(d.changes)
+	slices.SortStableFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
+
+// Invalidations returns every path that would have been invalidated, sorted
+func (d *DryRun) Invalidations() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	paths := slices.Clone(d.invalidations)
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
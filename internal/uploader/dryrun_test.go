@@ -0,0 +1,63 @@
+package uploader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	live := NewMemory()
+
+	require.NoError(t, Publish(ctx, live, "run-1", releaseArtifacts("YES"), false, 1))
+	before := live.Keys()
+	invalidationsBefore := live.Invalidations()
+
+	dryRun := NewDryRun(live)
+	artifacts := append(releaseArtifacts("NO"), Artifact{Key: "robots.txt", ContentType: "text/plain", Contents: []byte("User-agent: *\n")})
+	require.NoError(t, Publish(ctx, dryRun, "run-2", artifacts, false, 1))
+
+	// nothing changed
+	assert.Equal(t, before, live.Keys())
+	assert.Equal(t, invalidationsBefore, live.Invalidations())
+	assert.Equal(t, "<p>YES</p>", liveContents(t, live, "index.html"))
+
+	changes := map[string]Change{}
+	for _, curr := range dryRun.Changes() {
+		changes[curr.Key] = curr
+	}
+
+	assert.Equal(t, ActionUpdate, changes["index.html"].Action)
+	assert.Contains(t, changes["index.html"].Diff, "-<p>YES</p>")
+	assert.Contains(t, changes["index.html"].Diff, "+<p>NO</p>")
+	assert.Equal(t, ActionCreate, changes["robots.txt"].Action)
+	assert.Empty(t, changes["robots.txt"].Diff)
+
+	// the release is staged, and the one that fell off the end is deleted
+	assert.Equal(t, ActionCreate, changes["releases/run-2/index.html"].Action)
+	assert.Equal(t, ActionUpdate, changes[ReleaseIndexKey].Action)
+	assert.Equal(t, ActionDelete, changes["releases/run-1/index.html"].Action)
+
+	assert.Equal(t, []string{"/index.html", "/robots.txt", "/todays_events.json"}, dryRun.Invalidations())
+}
+
+func TestDryRun_Compressed(t *testing.T) {
+	ctx := context.Background()
+	live := NewMemory()
+
+	dryRun := NewDryRun(live)
+	require.NoError(t, Upload(ctx, NewCompressed(dryRun), releaseArtifacts("YES"), false))
+
+	assert.Empty(t, live.Keys())
+
+	var keys []string
+	for _, curr := range dryRun.Changes() {
+		keys = append(keys, curr.Key)
+		assert.Equal(t, ActionCreate, curr.Action)
+	}
+	assert.Equal(t, []string{"index.html", "index.html.br", "index.html.gz", "todays_events.json", "todays_events.json.br", "todays_events.json.gz"}, keys)
+	assert.Contains(t, dryRun.Invalidations(), "/index.html.br")
+}
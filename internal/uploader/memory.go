@@ -0,0 +1,96 @@
+package uploader
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Memory publishes to a map, so tests can look at exactly what would have been published
+type Memory struct {
+	mu sync.Mutex
+
+	objects       map[string]Artifact
+	invalidations [][]string
+}
+
+func NewMemory() *Memory {
+	return &Memory{objects: map[string]Artifact{}}
+}
+
+func (p *Memory) Put(_ context.Context, artifact Artifact) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	artifact.Contents = slices.Clone(artifact.Contents)
+	p.objects[artifact.Key] = artifact
+	return nil
+}
+
+func (p *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	obj, ok := p.objects[key]
+	if !ok {
+		return nil, nil
+	}
+	return slices.Clone(obj.Contents), nil
+}
+
+func (p *Memory) Hash(_ context.Context, key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	obj, ok := p.objects[key]
+	if !ok {
+		return "", nil
+	}
+	return ContentHash(obj), nil
+}
+
+func (p *Memory) Delete(_ context.Context, key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.objects, key)
+	return nil
+}
+
+func (p *Memory) Invalidate(_ context.Context, paths []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.invalidations = append(p.invalidations, slices.Clone(paths))
+	return nil
+}
+
+// Object returns what was published at key, and whether anything was
+func (p *Memory) Object(key string) (Artifact, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	obj, ok := p.objects[key]
+	return obj, ok
+}
+
+// Keys returns every key that has been published, sorted
+func (p *Memory) Keys() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, 0, len(p.objects))
+	for curr := range p.objects {
+		keys = append(keys, curr)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Invalidations returns the paths from every call to Invalidate, in order
+func (p *Memory) Invalidations() [][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.invalidations)
+}
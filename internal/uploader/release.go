@@ -0,0 +1,401 @@
+package uploader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// ReleasesPrefix is where every run's artifacts are staged before they go live
+	ReleasesPrefix = "releases/"
+
+	// ReleaseIndexKey is the pointer to the live release, along with the releases we're keeping around
+	ReleaseIndexKey = ReleasesPrefix + "index.json"
+
+	// DefaultKeepReleases is how many releases we keep to roll back to. Older ones are deleted.
+	DefaultKeepReleases = 14
+
+	manifestFile = "manifest.json"
+
+	// releaseCachePolicy is for everything under ReleasesPrefix. Staged artifacts never change once they're written,
+	// and the index is read by us, not browsers.
+	releaseCachePolicy = "no-store"
+
+	contentTypeJSON = "application/json"
+)
+
+// ReleaseArtifact is an artifact in a release's manifest
+type ReleaseArtifact struct {
+	Key             string `json:"key"`
+	ContentType     string `json:"content_type"`
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	CacheControl    string `json:"cache_control"`
+	Hash            string `json:"hash"`
+
+	// StagedIn is the release the contents are staged under. Artifacts that haven't changed since an earlier release
+	// aren't staged again, they point at that release's copy. Empty means the release the manifest is for.
+	StagedIn string `json:"staged_in,omitempty"`
+}
+
+// Manifest is everything that was published in a release
+type Manifest struct {
+	ID        string            `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Artifacts []ReleaseArtifact `json:"artifacts"`
+}
+
+// stagedIn is the release the contents of one of the manifest's artifacts are staged under
+func (m *Manifest) stagedIn(a ReleaseArtifact) string {
+	if a.StagedIn == "" {
+		return m.ID
+	}
+	return a.StagedIn
+}
+
+// stagedKey is where the contents of one of the manifest's artifacts are staged
+func (m *Manifest) stagedKey(a ReleaseArtifact) string {
+	return releaseKey(m.stagedIn(a), a.Key)
+}
+
+// Release is a release we still have staged
+type Release struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReleaseIndex points at the live release. Releases is newest first.
+type ReleaseIndex struct {
+	Current  string    `json:"current"`
+	Releases []Release `json:"releases"`
+}
+
+// NewRunID is a release ID for a run starting now. They sort in the order they were created.
+func NewRunID(now time.Time) string {
+	return now.UTC().Format("20060102T150405Z")
+}
+
+func releaseKey(id string, key string) string {
+	return ReleasesPrefix + id + "/" + key
+}
+
+// stagingPublisher is where releases are staged. Compressed variants are made when a release goes live, so there's no
+// point in staging them too.
+func stagingPublisher(publisher Publisher) Publisher {
+	if compressed, ok := publisher.(*Compressed); ok {
+		return compressed.Publisher
+	}
+	return publisher
+}
+
+func putJSON(ctx context.Context, publisher Publisher, key string, v any) error {
+	contents, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return publisher.Put(ctx, Artifact{
+		Key:          key,
+		ContentType:  contentTypeJSON,
+		CacheControl: releaseCachePolicy,
+		Contents:     contents,
+	})
+}
+
+func getJSON(ctx context.Context, publisher Publisher, key string, v any) (bool, error) {
+	contents, err := publisher.Get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if contents == nil {
+		return false, nil
+	}
+
+	err = json.Unmarshal(contents, v)
+	if err != nil {
+		return false, fmt.Errorf("could not parse %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Releases returns the release index. It is empty if nothing has been published as a release yet.
+func Releases(ctx context.Context, publisher Publisher) (*ReleaseIndex, error) {
+	var index ReleaseIndex
+	_, err := getJSON(ctx, stagingPublisher(publisher), ReleaseIndexKey, &index)
+	if err != nil {
+		return nil, fmt.Errorf("uploader: Releases: %w", err)
+	}
+	return &index, nil
+}
+
+// getManifest reads a release's manifest. It returns nil with no error if the release has no manifest.
+func getManifest(ctx context.Context, publisher Publisher, id string) (*Manifest, error) {
+	var manifest Manifest
+	found, err := getJSON(ctx, publisher, releaseKey(id, manifestFile), &manifest)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// stage writes the artifacts that changed since the previous release (which may be nil) under the release's prefix,
+// and a manifest of all of them. Unchanged artifacts point at the previous release's staged copy instead.
+func stage(ctx context.Context, publisher Publisher, manifest *Manifest, previous *Manifest, artifacts []Artifact) error {
+	type version struct {
+		key  string
+		hash string
+	}
+
+	// where each version of an artifact in the previous release is staged
+	stagedIn := map[version]string{}
+	if previous != nil {
+		for _, curr := range previous.Artifacts {
+			stagedIn[version{key: curr.Key, hash: curr.Hash}] = previous.stagedIn(curr)
+		}
+	}
+
+	eg, ctx2 := errgroup.WithContext(ctx)
+	for _, curr := range artifacts {
+		entry := ReleaseArtifact{
+			Key:             curr.Key,
+			ContentType:     curr.ContentType,
+			ContentEncoding: curr.ContentEncoding,
+			CacheControl:    curr.CacheControl,
+			Hash:            ContentHash(curr),
+		}
+
+		if id, ok := stagedIn[version{key: entry.Key, hash: entry.Hash}]; ok {
+			entry.StagedIn = id
+		} else {
+			eg.Go(func() error {
+				staged := curr
+				staged.Key = releaseKey(manifest.ID, curr.Key)
+				staged.CacheControl = releaseCachePolicy
+				return publisher.Put(ctx2, staged)
+			})
+		}
+
+		manifest.Artifacts = append(manifest.Artifacts, entry)
+	}
+
+	err := eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	return putJSON(ctx, publisher, releaseKey(manifest.ID, manifestFile), manifest)
+}
+
+// deleteReleases deletes the manifests of the given releases, along with everything staged for them that none of the
+// kept releases point at
+func deleteReleases(ctx context.Context, publisher Publisher, ids []string, kept []Release) error {
+	// if we can't tell what is still in use, it's safer to leave everything where it is
+	inUse := map[string]bool{}
+	for _, curr := range kept {
+		manifest, err := getManifest(ctx, publisher, curr.ID)
+		if err != nil {
+			return err
+		}
+		if manifest == nil {
+			continue
+		}
+		for _, artifact := range manifest.Artifacts {
+			inUse[manifest.stagedKey(artifact)] = true
+		}
+	}
+
+	for _, id := range ids {
+		log.Info().Str("run_id", id).Msg("deleting old release")
+
+		manifest, err := getManifest(ctx, publisher, id)
+		if err != nil {
+			return err
+		}
+
+		if manifest != nil {
+			for _, curr := range manifest.Artifacts {
+				key := manifest.stagedKey(curr)
+				if inUse[key] {
+					continue
+				}
+				err = publisher.Delete(ctx, key)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		err = publisher.Delete(ctx, releaseKey(id, manifestFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flip points the release index at the given release, adding it if it's new, and deletes anything past the newest keep
+// releases. The live release is never deleted, and neither is anything staged for an older release that a kept one
+// still points at.
+func flip(ctx context.Context, publisher Publisher, release Release, keep int) error {
+	var index ReleaseIndex
+	_, err := getJSON(ctx, publisher, ReleaseIndexKey, &index)
+	if err != nil {
+		return err
+	}
+
+	index.Current = release.ID
+	if !slices.ContainsFunc(index.Releases, func(r Release) bool { return r.ID == release.ID }) {
+		index.Releases = append([]Release{release}, index.Releases...)
+	}
+
+	var pruned []string
+	for len(index.Releases) > keep {
+		last := index.Releases[len(index.Releases)-1]
+		if last.ID == index.Current {
+			break
+		}
+		pruned = append(pruned, last.ID)
+		index.Releases = index.Releases[:len(index.Releases)-1]
+	}
+
+	err = putJSON(ctx, publisher, ReleaseIndexKey, &index)
+	if err != nil {
+		return err
+	}
+
+	// the index doesn't point at these anymore, so if deleting them fails they're just taking up space
+	if len(pruned) > 0 {
+		err = deleteReleases(ctx, publisher, pruned, index.Releases)
+		if err != nil {
+			log.Warn().Err(err).Strs("run_ids", pruned).Msg("could not delete old releases")
+		}
+	}
+
+	return nil
+}
+
+// republish puts a release we still have staged back on the live site. Only what differs from the live site is
+// uploaded and invalidated.
+func republish(ctx context.Context, publisher Publisher, runID string) error {
+	staging := stagingPublisher(publisher)
+
+	manifest, err := getManifest(ctx, staging, runID)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return fmt.Errorf("release %s has no manifest", runID)
+	}
+
+	artifacts := make([]Artifact, len(manifest.Artifacts))
+	for i, curr := range manifest.Artifacts {
+		contents, err := staging.Get(ctx, manifest.stagedKey(curr))
+		if err != nil {
+			return err
+		}
+
+		artifacts[i] = Artifact{
+			Key:             curr.Key,
+			ContentType:     curr.ContentType,
+			ContentEncoding: curr.ContentEncoding,
+			CacheControl:    curr.CacheControl,
+			Contents:        contents,
+		}
+		if contents == nil || ContentHash(artifacts[i]) != curr.Hash {
+			return fmt.Errorf("release %s is missing or has a corrupt copy of %s", runID, curr.Key)
+		}
+	}
+
+	log.Info().Str("run_id", runID).Int("artifact_count", len(artifacts)).Msg("republishing release")
+	return Upload(ctx, publisher, artifacts, false)
+}
+
+// Publish publishes the artifacts as a new release. Everything that changed since the live release is staged under the
+// run's prefix first, so if any of it fails the live site isn't touched at all. Then the release goes live with Upload,
+// the release index is pointed at it, and all but the newest keep releases are deleted. If going live fails part way
+// through, the live release is put back before returning the error.
+func Publish(ctx context.Context, publisher Publisher, runID string, artifacts []Artifact, invalidateAll bool, keep int) error {
+	staging := stagingPublisher(publisher)
+
+	withDefaults := make([]Artifact, len(artifacts))
+	for i, curr := range artifacts {
+		withDefaults[i] = curr.withDefaults()
+	}
+
+	index, err := Releases(ctx, publisher)
+	if err != nil {
+		return fmt.Errorf("uploader: Publish: could not read the release index, live site is unchanged: %w", err)
+	}
+
+	var previous *Manifest
+	if index.Current != "" {
+		previous, err = getManifest(ctx, staging, index.Current)
+		if err != nil {
+			return fmt.Errorf("uploader: Publish: could not read release %s, live site is unchanged: %w", index.Current, err)
+		}
+	}
+
+	release := Release{ID: runID, CreatedAt: time.Now().UTC()}
+	manifest := &Manifest{ID: release.ID, CreatedAt: release.CreatedAt}
+
+	log.Info().Str("run_id", runID).Int("artifact_count", len(artifacts)).Msg("staging release")
+	err = stage(ctx, staging, manifest, previous, withDefaults)
+	if err != nil {
+		return fmt.Errorf("uploader: Publish: could not stage release %s, live site is unchanged: %w", runID, err)
+	}
+
+	err = Upload(ctx, publisher, withDefaults, invalidateAll)
+	if err != nil {
+		if index.Current == "" {
+			return fmt.Errorf("uploader: Publish: could not make release %s live, and there is no previous release to go back to: %w", runID, err)
+		}
+
+		log.Error().Err(err).Str("run_id", runID).Str("previous_run_id", index.Current).Msg("could not make release live, putting the previous one back")
+		restoreErr := republish(ctx, publisher, index.Current)
+		if restoreErr != nil {
+			return fmt.Errorf("uploader: Publish: could not make release %s live, and could not put release %s back (%w), roll back to it to fix: %w", runID, index.Current, restoreErr, err)
+		}
+		return fmt.Errorf("uploader: Publish: could not make release %s live, release %s is still live: %w", runID, index.Current, err)
+	}
+
+	err = flip(ctx, staging, release, keep)
+	if err != nil {
+		return fmt.Errorf("uploader: Publish: release %s is live, but could not update the release index: %w", runID, err)
+	}
+
+	log.Info().Str("run_id", runID).Msg("release is live")
+	return nil
+}
+
+// Rollback republishes a release we still have staged and points the release index back at it. Anything published
+// since that isn't part of the release (like newer archive pages) is left alone.
+func Rollback(ctx context.Context, publisher Publisher, runID string) error {
+	index, err := Releases(ctx, publisher)
+	if err != nil {
+		return err
+	}
+
+	i := slices.IndexFunc(index.Releases, func(r Release) bool { return r.ID == runID })
+	if i == -1 {
+		return fmt.Errorf("uploader: Rollback: no release %s (have %d releases)", runID, len(index.Releases))
+	}
+
+	log.Info().Str("run_id", runID).Msg("rolling back")
+	err = republish(ctx, publisher, runID)
+	if err != nil {
+		return fmt.Errorf("uploader: Rollback: could not republish release %s: %w", runID, err)
+	}
+
+	err = flip(ctx, stagingPublisher(publisher), index.Releases[i], len(index.Releases))
+	if err != nil {
+		return fmt.Errorf("uploader: Rollback: release %s is live, but could not update the release index: %w", runID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func releaseArtifacts(answer string) []Artifact {
+	return []Artifact{
+		{Key: "index.html", ContentType: "text/html", Contents: []byte("<p>" + answer + "</p>")},
+		{Key: "todays_events.json", ContentType: "application/json", Contents: []byte(`{"answer":"` + answer + `"}`)},
+	}
+}
+
+func liveContents(t *testing.T, publisher *Memory, key string) string {
+	t.Helper()
+	obj, ok := publisher.Object(key)
+	require.True(t, ok, "%s isn't published", key)
+	return string(obj.Contents)
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	publisher := NewMemory()
+
+	require.NoError(t, Publish(ctx, publisher, "run-1", releaseArtifacts("YES"), false, 2))
+	require.NoError(t, Publish(ctx, publisher, "run-2", releaseArtifacts("NO"), false, 2))
+
+	assert.Equal(t, "<p>NO</p>", liveContents(t, publisher, "index.html"))
+	assert.Equal(t, "<p>YES</p>", liveContents(t, publisher, "releases/run-1/index.html"))
+	assert.Equal(t, "<p>NO</p>", liveContents(t, publisher, "releases/run-2/index.html"))
+
+	index, err := Releases(ctx, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, "run-2", index.Current)
+	require.Len(t, index.Releases, 2)
+	assert.Equal(t, "run-2", index.Releases[0].ID)
+	assert.Equal(t, "run-1", index.Releases[1].ID)
+
+	// staging isn't invalidated, only what went live
+	assert.Equal(t, []string{"/index.html", "/todays_events.json"}, publisher.Invalidations()[1])
+
+	// only the newest two are kept
+	require.NoError(t, Publish(ctx, publisher, "run-3", releaseArtifacts("MAYBE"), false, 2))
+	_, ok := publisher.Object("releases/run-1/index.html")
+	assert.False(t, ok)
+	_, ok = publisher.Object("releases/run-1/manifest.json")
+	assert.False(t, ok)
+
+	index, err = Releases(ctx, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-3", "run-2"}, []string{index.Releases[0].ID, index.Releases[1].ID})
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	publisher := NewMemory()
+
+	require.NoError(t, Publish(ctx, publisher, "run-1", releaseArtifacts("YES"), false, DefaultKeepReleases))
+	require.NoError(t, Publish(ctx, publisher, "run-2", releaseArtifacts("NO"), false, DefaultKeepReleases))
+
+	require.NoError(t, Rollback(ctx, publisher, "run-1"))
+
+	assert.Equal(t, "<p>YES</p>", liveContents(t, publisher, "index.html"))
+	assert.Equal(t, `{"answer":"YES"}`, liveContents(t, publisher, "todays_events.json"))
+	assert.Equal(t, []string{"/index.html", "/todays_events.json"}, publisher.Invalidations()[2])
+
+	index, err := Releases(ctx, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", index.Current)
+	// rolling back doesn't forget about the newer release, so we can roll forward again
+	assert.Len(t, index.Releases, 2)
+
+	require.NoError(t, Rollback(ctx, publisher, "run-2"))
+	assert.Equal(t, "<p>NO</p>", liveContents(t, publisher, "index.html"))
+
+	assert.ErrorContains(t, Rollback(ctx, publisher, "run-0"), "no release run-0")
+
+	// a staged copy that doesn't match its manifest isn't put live
+	require.NoError(t, publisher.Put(ctx, Artifact{Key: "releases/run-1/index.html", ContentType: "text/html", Contents: []byte("garbage")}))
+	assert.ErrorContains(t, Rollback(ctx, publisher, "run-1"), "corrupt")
+	assert.Equal(t, "<p>NO</p>", liveContents(t, publisher, "index.html"))
+}
+
+func TestPublish_UnchangedNotStaged(t *testing.T) {
+	ctx := context.Background()
+	publisher := NewMemory()
+
+	require.NoError(t, Publish(ctx, publisher, "run-1", releaseArtifacts("YES"), false, 1))
+
+	// the JSON is the same as run 1's, so it isn't staged again
+	run2 := []Artifact{releaseArtifacts("NO")[0], releaseArtifacts("YES")[1]}
+	require.NoError(t, Publish(ctx, publisher, "run-2", run2, false, 1))
+
+	assert.Equal(t, "<p>NO</p>", liveContents(t, publisher, "releases/run-2/index.html"))
+	_, ok := publisher.Object("releases/run-2/todays_events.json")
+	assert.False(t, ok)
+
+	manifest, err := getManifest(ctx, publisher, "run-2")
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", manifest.Artifacts[1].StagedIn)
+
+	// run 1 was pruned, but run 2 still needs its copy of the JSON
+	_, ok = publisher.Object("releases/run-1/manifest.json")
+	assert.False(t, ok)
+	_, ok = publisher.Object("releases/run-1/index.html")
+	assert.False(t, ok)
+	assert.Equal(t, `{"answer":"YES"}`, liveContents(t, publisher, "releases/run-1/todays_events.json"))
+
+	require.NoError(t, Rollback(ctx, publisher, "run-2"))
+
+	// once nothing points at it anymore, it goes too
+	require.NoError(t, Publish(ctx, publisher, "run-3", releaseArtifacts("MAYBE"), false, 1))
+	_, ok = publisher.Object("releases/run-1/todays_events.json")
+	assert.False(t, ok)
+	_, ok = publisher.Object("releases/run-2/index.html")
+	assert.False(t, ok)
+}
+
+// goLiveFailurePublisher can stage releases, but can't put anything on the live site once broken is set
+type goLiveFailurePublisher struct {
+	*Memory
+	broken         bool
+	restoreBroken  bool
+	restoreStarted bool
+}
+
+func (p *goLiveFailurePublisher) Put(ctx context.Context, artifact Artifact) error {
+	if strings.HasPrefix(artifact.Key, ReleasesPrefix) || !p.broken {
+		return p.Memory.Put(ctx, artifact)
+	}
+
+	// the release going live gets part way before failing
+	if strings.Contains(string(artifact.Contents), "YES") {
+		p.restoreStarted = true
+		if p.restoreBroken {
+			return errors.New("bucket is still on fire")
+		}
+		return p.Memory.Put(ctx, artifact)
+	}
+	if artifact.Key == "todays_events.json" {
+		return errors.New("bucket is on fire")
+	}
+	return p.Memory.Put(ctx, artifact)
+}
+
+func TestPublish_GoingLiveFails(t *testing.T) {
+	ctx := context.Background()
+	publisher := &goLiveFailurePublisher{Memory: NewMemory()}
+
+	require.NoError(t, Publish(ctx, publisher, "run-1", releaseArtifacts("YES"), false, DefaultKeepReleases))
+
+	publisher.broken = true
+	err := Publish(ctx, publisher, "run-2", releaseArtifacts("NO"), false, DefaultKeepReleases)
+	assert.ErrorContains(t, err, "release run-1 is still live")
+	assert.True(t, publisher.restoreStarted)
+
+	// whatever of run 2 made it live was replaced with run 1 again
+	assert.Equal(t, "<p>YES</p>", liveContents(t, publisher.Memory, "index.html"))
+	assert.Equal(t, `{"answer":"YES"}`, liveContents(t, publisher.Memory, "todays_events.json"))
+
+	index, err := Releases(ctx, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", index.Current)
+
+	publisher.restoreBroken = true
+	err = Publish(ctx, publisher, "run-3", releaseArtifacts("NO"), false, DefaultKeepReleases)
+	assert.ErrorContains(t, err, "could not put release run-1 back")
+}
+
+// stagingFailurePublisher fails to stage anything for one release
+type stagingFailurePublisher struct {
+	*Memory
+}
+
+func (p stagingFailurePublisher) Put(ctx context.Context, artifact Artifact) error {
+	if artifact.Key == "releases/run-2/todays_events.json" {
+		return errors.New("bucket is on fire")
+	}
+	return p.Memory.Put(ctx, artifact)
+}
+
+func TestPublish_StagingFails(t *testing.T) {
+	ctx := context.Background()
+	publisher := stagingFailurePublisher{Memory: NewMemory()}
+
+	require.NoError(t, Publish(ctx, publisher, "run-1", releaseArtifacts("YES"), false, DefaultKeepReleases))
+
+	err := Publish(ctx, publisher, "run-2", releaseArtifacts("NO"), false, DefaultKeepReleases)
+	assert.ErrorContains(t, err, "live site is unchanged")
+
+	// none of run 2 made it live
+	assert.Equal(t, "<p>YES</p>", liveContents(t, publisher.Memory, "index.html"))
+	assert.Equal(t, `{"answer":"YES"}`, liveContents(t, publisher.Memory, "todays_events.json"))
+
+	index, err := Releases(ctx, publisher)
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", index.Current)
+	assert.Len(t, index.Releases, 1)
+}
+
+func TestPublish_Compressed(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemory()
+
+	require.NoError(t, Publish(ctx, NewCompressed(memory), "run-1", releaseArtifacts("YES"), false, DefaultKeepReleases))
+
+	// variants go live, but aren't staged
+	_, ok := memory.Object("index.html.br")
+	assert.True(t, ok)
+	_, ok = memory.Object("releases/run-1/index.html.br")
+	assert.False(t, ok)
+}
@@ -0,0 +1,155 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	EnvVarBucketName     = "UPLOAD_S3_BUCKET_NAME"
+	EnvVarDistributionID = "UPLOAD_CF_DISTRIBUTION_ID"
+
+	// hashMetadataKey is the object metadata we keep the ContentHash in. We can't use the ETag since it is only an MD5
+	// of the contents for some uploads, and it doesn't cover the content type or cache policy.
+	hashMetadataKey = "content-hash"
+)
+
+// S3 publishes to an S3 bucket with a CloudFront distribution in front of it
+type S3 struct {
+	s3Client       *s3.Client
+	cfClient       *cloudfront.Client
+	bucketName     string
+	distributionID string
+}
+
+// NewS3 loads the AWS config and creates a publisher for the given bucket and distribution
+func NewS3(ctx context.Context, bucketName string, distributionID string) (*S3, error) {
+	log.Info().Msg("loading AWS config for uploader")
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("uploader: NewS3: could not load AWS config: %w", err)
+	}
+
+	return &S3{
+		s3Client:       s3.NewFromConfig(cfg),
+		cfClient:       cloudfront.NewFromConfig(cfg),
+		bucketName:     bucketName,
+		distributionID: distributionID,
+	}, nil
+}
+
+func (p *S3) Put(ctx context.Context, artifact Artifact) error {
+	log.Info().Str("bucket", p.bucketName).Str("key", artifact.Key).Str("content_type", artifact.ContentType).Str("content_encoding", artifact.ContentEncoding).Msg("uploading object")
+
+	input := &s3.PutObjectInput{
+		Bucket:       aws.String(p.bucketName),
+		Key:          aws.String(artifact.Key),
+		Body:         bytes.NewReader(artifact.Contents),
+		ContentType:  aws.String(artifact.ContentType),
+		CacheControl: aws.String(artifact.CacheControl),
+		Metadata: map[string]string{
+			hashMetadataKey: ContentHash(artifact),
+		},
+	}
+	if artifact.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(artifact.ContentEncoding)
+	}
+
+	_, err := p.s3Client.PutObject(ctx, input)
+	if err != nil {
+		return fmt.Errorf("uploader: S3.Put: could not upload to S3: %s: %w", artifact.Key, err)
+	}
+
+	return nil
+}
+
+func (p *S3) Get(ctx context.Context, key string) ([]byte, error) {
+	log.Info().Str("bucket", p.bucketName).Str("key", key).Msg("fetching object")
+
+	res, err := p.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(p.bucketName),
+		Key:    aws.String(key),
+	})
+	if _, ok := errors.AsType[*s3types.NoSuchKey](err); ok {
+		log.Info().Str("key", key).Msg("object does not exist yet")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("uploader: S3.Get: could not get object from S3: %s: %w", key, err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Warn().Err(err).Msg("error closing S3 response")
+		}
+	}(res.Body)
+
+	contents, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("uploader: S3.Get: could not read object: %s: %w", key, err)
+	}
+
+	return contents, nil
+}
+
+func (p *S3) Hash(ctx context.Context, key string) (string, error) {
+	res, err := p.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(p.bucketName),
+		Key:    aws.String(key),
+	})
+	if _, ok := errors.AsType[*s3types.NotFound](err); ok {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("uploader: S3.Hash: could not get object metadata from S3: %s: %w", key, err)
+	}
+
+	// objects uploaded before we started hashing don't have this, so they get uploaded again once
+	return res.Metadata[hashMetadataKey], nil
+}
+
+func (p *S3) Delete(ctx context.Context, key string) error {
+	log.Info().Str("bucket", p.bucketName).Str("key", key).Msg("deleting object")
+
+	_, err := p.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(p.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("uploader: S3.Delete: could not delete from S3: %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (p *S3) Invalidate(ctx context.Context, paths []string) error {
+	log.Info().Str("distribution_id", p.distributionID).Int("path_count", len(paths)).Msg("invalidating CF cache")
+
+	_, err := p.cfClient.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(p.distributionID),
+		InvalidationBatch: &types.InvalidationBatch{
+			CallerReference: aws.String(time.Now().Format(time.RFC3339)),
+			Paths: &types.Paths{
+				Items:    paths,
+				Quantity: aws.Int32(int32(len(paths))),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("uploader: S3.Invalidate: %w", err)
+	}
+
+	return nil
+}
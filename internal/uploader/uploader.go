@@ -1,98 +1,181 @@
 package uploader
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"os"
-	"time"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
-	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
-	envVarBucketName     = "UPLOAD_S3_BUCKET_NAME"
-	envVarDistributionID = "UPLOAD_CF_DISTRIBUTION_ID"
+	// EnvVarPublishDir points at a directory to publish to instead of S3, for previewing the site locally
+	EnvVarPublishDir = "PUBLISH_DIR"
 
-	// we want browsers to always revalidate, cloudfront to hold on to things for a day (or until manual invalidation)
-	cachePolicy = "max-age=0, must-revalidate, s-maxage=86400"
+	// DefaultCachePolicy is used for artifacts that don't set their own. We want browsers to always revalidate,
+	// cloudfront to hold on to things for a day (or until manual invalidation)
+	DefaultCachePolicy = "max-age=0, must-revalidate, s-maxage=86400"
 )
 
+// Publisher is somewhere the site gets published to
+type Publisher interface {
+	// Put stores the artifact's contents at its key, to be served with its content type, encoding and cache policy
+	Put(ctx context.Context, artifact Artifact) error
+
+	// Get returns what is currently published at key. It returns nil with no error if nothing has been published there
+	// yet.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Hash returns the ContentHash of what is currently published at key. It returns an empty string if nothing has been
+	// published there or the publisher doesn't know, in which case the artifact is always published.
+	Hash(ctx context.Context, key string) (string, error)
+
+	// Delete removes whatever is published at key. Deleting something that doesn't exist isn't an error.
+	Delete(ctx context.Context, key string) error
+
+	// Invalidate tells whatever caches the site that the given paths have changed
+	Invalidate(ctx context.Context, paths []string) error
+}
+
 var (
-	s3Client *s3.Client
-	cfClient *cloudfront.Client
+	_ Publisher = (*S3)(nil)
+	_ Publisher = (*Dir)(nil)
+	_ Publisher = (*Memory)(nil)
+	_ Publisher = (*Compressed)(nil)
+	_ Publisher = (*DryRun)(nil)
 )
 
-func init() {
-	log.Info().Msg("loading AWS config for uploader")
-	cfg, err := config.LoadDefaultConfig(context.Background())
+// Artifact is a single rendered file that gets published to the site
+type Artifact struct {
+	Key         string
+	ContentType string
+	Contents    []byte
+
+	// ContentEncoding is set if Contents are compressed (e.g. "gzip")
+	ContentEncoding string
+
+	// CacheControl overrides DefaultCachePolicy for this artifact
+	CacheControl string
+}
+
+// withDefaults fills in anything the renderers left to the uploader
+func (a Artifact) withDefaults() Artifact {
+	if a.CacheControl == "" {
+		a.CacheControl = DefaultCachePolicy
+	}
+	return a
+}
+
+// ContentHash is what we compare to decide whether an artifact has changed since it was last published. It covers how
+// the artifact is served as well as its contents, so changing the content type or cache policy publishes it again too.
+func ContentHash(a Artifact) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n", a.ContentType, a.ContentEncoding, a.CacheControl)
+	_, _ = h.Write(a.Contents)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// changedArtifacts returns the artifacts whose hash doesn't match what is already published
+func changedArtifacts(ctx context.Context, publisher Publisher, artifacts []Artifact) ([]Artifact, error) {
+	eg, ctx2 := errgroup.WithContext(ctx)
+
+	unchanged := make([]bool, len(artifacts))
+	for i, curr := range artifacts {
+		eg.Go(func() error {
+			published, err := publisher.Hash(ctx2, curr.Key)
+			if err != nil {
+				return err
+			}
+			unchanged[i] = published != "" && published == ContentHash(curr)
+			return nil
+		})
+	}
+
+	err := eg.Wait()
 	if err != nil {
-		log.Fatal().Err(err).Msg("could not load AWS config")
+		return nil, err
+	}
+
+	var changed []Artifact
+	for i, curr := range artifacts {
+		if unchanged[i] {
+			log.Debug().Str("key", curr.Key).Msg("unchanged, skipping")
+			continue
+		}
+		changed = append(changed, curr)
 	}
 
-	log.Info().Msg("creating clients")
-	s3Client = s3.NewFromConfig(cfg)
-	cfClient = cloudfront.NewFromConfig(cfg)
+	return changed, nil
 }
 
-func uploadObject(ctx context.Context, contents []byte, key string, contentType string) error {
-	bucketName := os.Getenv(envVarBucketName)
-	log.Info().Str("bucket", bucketName).Str("key", key).Str("content_type", contentType).Msg("uploading object")
-
-	_, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket:       aws.String(bucketName),
-		Key:          aws.String(key),
-		Body:         bytes.NewReader(contents),
-		ContentType:  aws.String(contentType),
-		CacheControl: aws.String(cachePolicy),
-	})
+// Fetch downloads a previously published object so renderers can build on what is already live. It returns nil with
+// no error if the object doesn't exist yet.
+func Fetch(ctx context.Context, publisher Publisher, key string) ([]byte, error) {
+	contents, err := publisher.Get(ctx, key)
 	if err != nil {
-		return fmt.Errorf("uploader: Upload: could not upload to S3: %s: %w", key, err)
+		return nil, fmt.Errorf("uploader: Fetch: %w", err)
 	}
+	return contents, nil
+}
 
-	return nil
+// maxInvalidationPaths is how many paths get invalidated one by one before everything is invalidated instead. CloudFront
+// only gives 1,000 paths a month for free and a wildcard counts as one, so a daily run has to stay well under 30.
+const maxInvalidationPaths = 15
+
+// invalidationPaths is what actually gets invalidated for paths, which is everything once there are too many of them
+func invalidationPaths(paths []string) []string {
+	if len(paths) > maxInvalidationPaths {
+		log.Info().Int("path_count", len(paths)).Msg("too many paths changed, invalidating everything")
+		return []string{"/*"}
+	}
+	return paths
 }
 
-func Upload(ctx context.Context, htmlContents []byte, jsonContents []byte, invalidateAll bool) error {
+// Upload puts every artifact that has changed since it was last published to the publisher and then invalidates their
+// paths. If nothing changed, nothing is invalidated, unless invalidateAll is set, in which case everything is. So is
+// everything if too much changed to invalidate it path by path.
+func Upload(ctx context.Context, publisher Publisher, artifacts []Artifact, invalidateAll bool) error {
+	withDefaults := make([]Artifact, len(artifacts))
+	for i, curr := range artifacts {
+		withDefaults[i] = curr.withDefaults()
+	}
+
+	changed, err := changedArtifacts(ctx, publisher, withDefaults)
+	if err != nil {
+		return fmt.Errorf("upload: could not check for changes: %w", err)
+	}
+
+	log.Info().Int("artifact_count", len(artifacts)).Int("changed_count", len(changed)).Msg("checked for changes")
+
 	eg, ctx2 := errgroup.WithContext(ctx)
 
-	eg.Go(func() error {
-		return uploadObject(ctx2, htmlContents, "index.html", "text/html")
-	})
-	eg.Go(func() error {
-		return uploadObject(ctx2, jsonContents, "todays_events.json", "application/json")
-	})
+	pathList := make([]string, len(changed))
+	for i, curr := range changed {
+		eg.Go(func() error {
+			return publisher.Put(ctx2, curr)
+		})
+		pathList[i] = "/" + curr.Key
+	}
 
-	err := eg.Wait()
+	err = eg.Wait()
 	if err != nil {
-		return fmt.Errorf("upload: could not upload objects to S3: %w", err)
+		return fmt.Errorf("upload: could not upload objects: %w", err)
 	}
 
-	distributionID := os.Getenv(envVarDistributionID)
-	log.Info().Str("distribution_id", distributionID).Msg("invalidating CF cache")
-
-	pathList := []string{"/index.html", "/todays_events.json"}
 	if invalidateAll {
 		log.Info().Msg("invalidating everything")
 		pathList = []string{"/*"}
 	}
+	pathList = invalidationPaths(pathList)
+
+	if len(pathList) == 0 {
+		log.Info().Msg("nothing changed, not invalidating")
+		return nil
+	}
 
-	_, err = cfClient.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
-		DistributionId: aws.String(distributionID),
-		InvalidationBatch: &types.InvalidationBatch{
-			CallerReference: aws.String(time.Now().Format(time.RFC3339)),
-			Paths: &types.Paths{
-				Items:    pathList,
-				Quantity: aws.Int32(int32(len(pathList))),
-			},
-		},
-	})
+	err = publisher.Invalidate(ctx, pathList)
 	if err != nil {
 		return fmt.Errorf("uploader: Upload: could not invalidate cache: %w", err)
 	}
@@ -100,5 +183,4 @@ func Upload(ctx context.Context, htmlContents []byte, jsonContents []byte, inval
 	log.Info().Msg("upload done")
 
 	return nil
-
 }
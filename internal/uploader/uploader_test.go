@@ -0,0 +1,166 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testArtifacts = []Artifact{
+	{Key: "index.html", ContentType: "text/html", Contents: []byte("<p>YES</p>")},
+	{Key: "archive/2026/05/02.html", ContentType: "text/html", Contents: []byte("<p>YES</p>"), CacheControl: "max-age=31536000"},
+}
+
+func TestUpload(t *testing.T) {
+	publisher := NewMemory()
+
+	require.NoError(t, Upload(context.Background(), publisher, testArtifacts, false))
+
+	assert.Equal(t, []string{"archive/2026/05/02.html", "index.html"}, publisher.Keys())
+
+	index, ok := publisher.Object("index.html")
+	require.True(t, ok)
+	assert.Equal(t, Artifact{Key: "index.html", Contents: []byte("<p>YES</p>"), ContentType: "text/html", CacheControl: DefaultCachePolicy}, index)
+
+	archive, ok := publisher.Object("archive/2026/05/02.html")
+	require.True(t, ok)
+	assert.Equal(t, "max-age=31536000", archive.CacheControl)
+
+	contents, err := Fetch(context.Background(), publisher, "index.html")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>YES</p>", string(contents))
+
+	contents, err = Fetch(context.Background(), publisher, "nope.html")
+	require.NoError(t, err)
+	assert.Nil(t, contents)
+
+	require.NoError(t, Upload(context.Background(), publisher, testArtifacts, true))
+	assert.Equal(t, [][]string{{"/index.html", "/archive/2026/05/02.html"}, {"/*"}}, publisher.Invalidations())
+}
+
+type failingPublisher struct {
+	*Memory
+}
+
+func (failingPublisher) Put(context.Context, Artifact) error {
+	return errors.New("bucket is on fire")
+}
+
+func TestUpload_Error(t *testing.T) {
+	publisher := failingPublisher{Memory: NewMemory()}
+
+	err := Upload(context.Background(), publisher, testArtifacts, false)
+	assert.ErrorContains(t, err, "bucket is on fire")
+	// nothing gets invalidated if the upload didn't work
+	assert.Empty(t, publisher.Invalidations())
+}
+
+func TestDir(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "site")
+	publisher, err := NewDir(root)
+	require.NoError(t, err)
+
+	require.NoError(t, Upload(context.Background(), publisher, testArtifacts, false))
+
+	contents, err := os.ReadFile(filepath.Join(root, "archive", "2026", "05", "02.html"))
+	require.NoError(t, err)
+	assert.Equal(t, "<p>YES</p>", string(contents))
+
+	contents, err = Fetch(context.Background(), publisher, "index.html")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>YES</p>", string(contents))
+
+	contents, err = Fetch(context.Background(), publisher, "nope.html")
+	require.NoError(t, err)
+	assert.Nil(t, contents)
+
+	err = publisher.Put(context.Background(), Artifact{Key: "../escape.html", ContentType: "text/html", Contents: []byte("nope")})
+	assert.Error(t, err)
+}
+
+func TestOpenDir(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "site")
+	publisher := OpenDir(root)
+
+	contents, err := Fetch(ctx, publisher, "index.html")
+	require.NoError(t, err)
+	assert.Nil(t, contents)
+
+	assert.ErrorContains(t, publisher.Put(ctx, testArtifacts[0]), "read only")
+	assert.ErrorContains(t, publisher.Delete(ctx, "index.html"), "read only")
+	assert.NoDirExists(t, root)
+}
+
+func TestUpload_SkipsUnchanged(t *testing.T) {
+	publisher := NewMemory()
+
+	require.NoError(t, Upload(context.Background(), publisher, testArtifacts, false))
+	require.Len(t, publisher.Invalidations(), 1)
+
+	// nothing changed, so nothing is invalidated
+	require.NoError(t, Upload(context.Background(), publisher, testArtifacts, false))
+	assert.Len(t, publisher.Invalidations(), 1)
+
+	changed := []Artifact{
+		{Key: "index.html", ContentType: "text/html", Contents: []byte("<p>NO</p>")},
+		testArtifacts[1],
+		{Key: "new.html", ContentType: "text/html", Contents: []byte("<p>new</p>")},
+	}
+	require.NoError(t, Upload(context.Background(), publisher, changed, false))
+	assert.Equal(t, []string{"/index.html", "/new.html"}, publisher.Invalidations()[1])
+
+	index, _ := publisher.Object("index.html")
+	assert.Equal(t, "<p>NO</p>", string(index.Contents))
+
+	// how something is served counts as a change too
+	changed[0].CacheControl = "no-store"
+	require.NoError(t, Upload(context.Background(), publisher, changed, false))
+	assert.Equal(t, []string{"/index.html"}, publisher.Invalidations()[2])
+
+	// asking to invalidate everything does, even if nothing changed
+	require.NoError(t, Upload(context.Background(), publisher, changed, true))
+	assert.Equal(t, []string{"/*"}, publisher.Invalidations()[3])
+}
+
+func TestContentHash(t *testing.T) {
+	artifact := Artifact{Key: "index.html", ContentType: "text/html", Contents: []byte("<p>YES</p>"), CacheControl: DefaultCachePolicy}
+
+	hash := ContentHash(artifact)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, ContentHash(artifact))
+
+	changed := artifact
+	changed.Contents = []byte("<p>NO</p>")
+	assert.NotEqual(t, hash, ContentHash(changed))
+
+	changed = artifact
+	changed.ContentType = "text/plain"
+	assert.NotEqual(t, hash, ContentHash(changed))
+
+	changed = artifact
+	changed.ContentEncoding = "gzip"
+	assert.NotEqual(t, hash, ContentHash(changed))
+}
+
+func TestUpload_TooManyPaths(t *testing.T) {
+	var artifacts []Artifact
+	for i := range maxInvalidationPaths + 1 {
+		artifacts = append(artifacts, Artifact{Key: fmt.Sprintf("page-%d.html", i), ContentType: "text/html", Contents: []byte("<p>YES</p>")})
+	}
+
+	publisher := NewMemory()
+	require.NoError(t, Upload(context.Background(), publisher, artifacts, false))
+	assert.Equal(t, [][]string{{"/*"}}, publisher.Invalidations())
+
+	// a few changes are still invalidated one by one
+	artifacts[0].Contents = []byte("<p>NO</p>")
+	require.NoError(t, Upload(context.Background(), publisher, artifacts, false))
+	assert.Equal(t, []string{"/page-0.html"}, publisher.Invalidations()[1])
+}
@@ -20,6 +20,11 @@ func main() {
 		log.Fatal().Err(err).Msg("could not initialize secrets manager client")
 	}
 
+	err = handler.Init()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not initialize handler")
+	}
+
 	if os.Getenv("_HANDLER") != "" {
 		lambda.Start(handler.EventHandler)
 	} else {